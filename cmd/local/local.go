@@ -24,7 +24,7 @@ func LocalCommand() *cobra.Command {
 		RunE:  localRun,
 	}
 	cmd.Flags().StringSlice("databases", []string{}, "databases to test")
-	cmd.Flags().String("database_type", "postgres", "database type to test")
+	cmd.Flags().String("database_type", "postgres", "default database type for databases without a type")
 	cmd.Flags().Int("test_timeout", 5, "test timeout in seconds")
 	viper.BindPFlag("databases", cmd.Flags().Lookup("databases"))
 	viper.BindPFlag("database_type", cmd.Flags().Lookup("database_type"))
@@ -51,21 +51,15 @@ func local(cfg *LocalCfg, ctx context.Context) error {
 	log.Info().Msg("Starting local")
 
 	log.Debug().Msg("Initializing database tester")
-	dbs := []database.Database{}
-	for _, dbCfg := range cfg.Databases {
-		switch cfg.DatabaseType {
-		case "sqlite":
-			log.Debug().Msg("Using sqlite")
-			dbs = append(dbs, database.NewSQLite(dbCfg))
-		case "postgres":
-			log.Debug().Msg("Using postgres")
-			dbs = append(dbs, database.NewPostgres(dbCfg))
-		}
+	dbs, err := database.NewAll(cfg.Databases, cfg.DatabaseType)
+	if err != nil {
+		return err
 	}
 	tester := tester.New(tester.Config{
 		Databases:    dbs,
 		TestTimeout:  cfg.TestTimeout,
 		TestInterval: cfg.TestInterval,
+		Clusters:     database.Clusters(cfg.Databases, dbs),
 	})
 	log.Info().Msg("Starting database tester")
 	result := tester.Run(ctx)
@@ -3,6 +3,7 @@ package serve
 import (
 	"context"
 
+	"github.com/fbufler/database-monitor/internal/resolver"
 	"github.com/fbufler/database-monitor/internal/service"
 	"github.com/fbufler/database-monitor/internal/tester"
 	"github.com/fbufler/database-monitor/pkg/database"
@@ -13,12 +14,20 @@ import (
 )
 
 type ServeCfg struct {
-	Databases        []database.Config `mapstructure:"databases"`
-	DatabaseType     string            `mapstructure:"database_type"`
-	TestTimeout      int               `mapstructure:"test_timeout"`
-	TestInterval     int               `mapstructure:"test_interval"`
-	Port             int               `mapstructure:"port"`
-	InvalidationTime int               `mapstructure:"invalidation_time"`
+	Databases                  []database.Config `mapstructure:"databases"`
+	DatabaseType               string            `mapstructure:"database_type"`
+	TestTimeout                int               `mapstructure:"test_timeout"`
+	TestInterval               int               `mapstructure:"test_interval"`
+	Port                       int               `mapstructure:"port"`
+	InvalidationTime           int               `mapstructure:"invalidation_time"`
+	DNSPort                    int               `mapstructure:"dns_port"`
+	DNSDomain                  string            `mapstructure:"dns_domain"`
+	DNSTTL                     int               `mapstructure:"dns_ttl"`
+	VacuumInterval             int               `mapstructure:"vacuum_interval"`
+	StatementsTop              int               `mapstructure:"statements_top"`
+	StatementsRegressionFactor float64           `mapstructure:"statements_regression_factor"`
+	SequencesInterval          int               `mapstructure:"sequences_interval"`
+	IgnoredSettings            []string          `mapstructure:"ignored_settings"`
 }
 
 func ServeCommand() *cobra.Command {
@@ -28,17 +37,33 @@ func ServeCommand() *cobra.Command {
 		RunE:  serveRun,
 	}
 	cmd.Flags().StringSlice("databases", []string{}, "databases to test")
-	cmd.Flags().String("database_type", "postgres", "database type to test")
+	cmd.Flags().String("database_type", "postgres", "default database type for databases without a type")
 	cmd.Flags().Int("test_timeout", 5, "test timeout in seconds")
 	cmd.Flags().Int("test_interval", 5, "test interval in seconds")
 	cmd.Flags().Int("port", 8080, "service port")
 	cmd.Flags().Int("invalidation_time", 5, "invalidation time in seconds")
+	cmd.Flags().Int("dns_port", 0, "DNS resolver port (0 disables the resolver)")
+	cmd.Flags().String("dns_domain", "dbm", "DNS zone the resolver answers for")
+	cmd.Flags().Int("dns_ttl", 5, "TTL of DNS answers in seconds")
+	cmd.Flags().Int("vacuum_interval", 300, "vacuum statistics interval in seconds")
+	cmd.Flags().Int("statements_top", 10, "number of queries reported per interval by time spent")
+	cmd.Flags().Float64("statements_regression_factor", 2, "factor by which a query's mean time has to exceed its baseline to be flagged")
+	cmd.Flags().Int("sequences_interval", 3600, "sequence exhaustion check interval in seconds")
+	cmd.Flags().StringSlice("ignored_settings", []string{}, "settings left out when comparing cluster members")
 	viper.BindPFlag("databases", cmd.Flags().Lookup("databases"))
 	viper.BindPFlag("database_type", cmd.Flags().Lookup("database_type"))
 	viper.BindPFlag("test_timeout", cmd.Flags().Lookup("test_timeout"))
 	viper.BindPFlag("test_interval", cmd.Flags().Lookup("test_interval"))
 	viper.BindPFlag("port", cmd.Flags().Lookup("port"))
 	viper.BindPFlag("invalidation_time", cmd.Flags().Lookup("invalidation_time"))
+	viper.BindPFlag("dns_port", cmd.Flags().Lookup("dns_port"))
+	viper.BindPFlag("dns_domain", cmd.Flags().Lookup("dns_domain"))
+	viper.BindPFlag("dns_ttl", cmd.Flags().Lookup("dns_ttl"))
+	viper.BindPFlag("vacuum_interval", cmd.Flags().Lookup("vacuum_interval"))
+	viper.BindPFlag("statements_top", cmd.Flags().Lookup("statements_top"))
+	viper.BindPFlag("statements_regression_factor", cmd.Flags().Lookup("statements_regression_factor"))
+	viper.BindPFlag("sequences_interval", cmd.Flags().Lookup("sequences_interval"))
+	viper.BindPFlag("ignored_settings", cmd.Flags().Lookup("ignored_settings"))
 	return cmd
 }
 
@@ -61,21 +86,19 @@ func serve(cfg *ServeCfg, ctx context.Context) error {
 	log.Info().Msg("Starting local")
 
 	log.Debug().Msg("Initializing database tester")
-	dbs := []database.Database{}
-	for _, dbCfg := range cfg.Databases {
-		switch cfg.DatabaseType {
-		case "sqlite":
-			log.Debug().Msg("Using sqlite")
-			dbs = append(dbs, database.NewSQLite(dbCfg))
-		case "postgres":
-			log.Debug().Msg("Using postgres")
-			dbs = append(dbs, database.NewPostgres(dbCfg))
-		}
+	dbs, err := database.NewAll(cfg.Databases, cfg.DatabaseType)
+	if err != nil {
+		return err
 	}
 	tester := tester.New(tester.Config{
-		Databases:    dbs,
-		TestTimeout:  cfg.TestTimeout,
-		TestInterval: cfg.TestInterval,
+		Databases:                  dbs,
+		TestTimeout:                cfg.TestTimeout,
+		TestInterval:               cfg.TestInterval,
+		Clusters:                   database.Clusters(cfg.Databases, dbs),
+		VacuumInterval:             cfg.VacuumInterval,
+		StatementsTop:              cfg.StatementsTop,
+		StatementsRegressionFactor: cfg.StatementsRegressionFactor,
+		SequencesInterval:          cfg.SequencesInterval,
 	})
 	log.Info().Msg("Starting database tester")
 	result := tester.Run(ctx)
@@ -84,9 +107,24 @@ func serve(cfg *ServeCfg, ctx context.Context) error {
 	service := service.New(service.Config{
 		Port:             cfg.Port,
 		InvalidationTime: cfg.InvalidationTime,
+		IgnoredSettings:  cfg.IgnoredSettings,
 	}, result, router)
 	log.Info().Msg("Starting service")
 	go service.Run(ctx)
+	if cfg.DNSPort > 0 {
+		log.Info().Msg("Starting DNS resolver")
+		hosts := make(map[string]string)
+		for i, db := range dbs {
+			hosts[db.Identifier()] = cfg.Databases[i].Host
+		}
+		resolver := resolver.New(resolver.Config{
+			Port:   cfg.DNSPort,
+			Domain: cfg.DNSDomain,
+			TTL:    cfg.DNSTTL,
+			Hosts:  hosts,
+		}, service)
+		go resolver.Run(ctx)
+	}
 	log.Debug().Msg("Waiting for context termination")
 	<-ctx.Done()
 	log.Info().Msg("Context terminated")
@@ -22,7 +22,7 @@ func SetupCommand() *cobra.Command {
 		RunE:  runSetup,
 	}
 	cmd.Flags().StringSlice("databases", []string{}, "databases to test")
-	cmd.Flags().String("database_type", "postgres", "database type to test")
+	cmd.Flags().String("database_type", "postgres", "default database type for databases without a type")
 	viper.BindPFlag("databases", cmd.Flags().Lookup("databases"))
 	viper.BindPFlag("database_type", cmd.Flags().Lookup("database_type"))
 	return cmd
@@ -42,22 +42,15 @@ func runSetup(cmd *cobra.Command, args []string) error {
 
 func Setup(cfg *SetupCfg, ctx context.Context) error {
 	log.Debug().Msg("Initializing database tester")
-	dbs := []database.Database{}
-	for _, dbCfg := range cfg.Databases {
-		switch cfg.DatabaseType {
-		case "sqlite":
-			log.Debug().Msg("Using sqlite")
-			dbs = append(dbs, database.NewSQLite(dbCfg))
-		case "postgres":
-			log.Debug().Msg("Using postgres")
-			dbs = append(dbs, database.NewPostgres(dbCfg))
-		}
+	dbs, err := database.NewAll(cfg.Databases, cfg.DatabaseType)
+	if err != nil {
+		return err
 	}
 	tester := tester.New(tester.Config{
 		Databases: dbs,
 	})
 	log.Info().Msg("Setup tester")
-	err := tester.Setup(ctx)
+	err = tester.Setup(ctx)
 	if err != nil {
 		return err
 	}
@@ -25,3 +25,37 @@ func TestSetup(t *testing.T) {
 	cancel()
 	os.Remove("test.db")
 }
+
+func TestSetupDatabaseType(t *testing.T) {
+	cfg := SetupCfg{
+		Databases: []database.Config{
+			{
+				Type:     "sqlite",
+				FilePath: "test.db",
+			},
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	err := Setup(&cfg, ctx)
+	if err != nil {
+		t.Errorf("Setup failed: %s", err)
+	}
+	cancel()
+	os.Remove("test.db")
+}
+
+func TestSetupUnknownDatabaseType(t *testing.T) {
+	cfg := SetupCfg{
+		Databases: []database.Config{
+			{
+				Type:     "oracle",
+				FilePath: "test.db",
+			},
+		},
+		DatabaseType: "sqlite",
+	}
+	err := Setup(&cfg, context.Background())
+	if err == nil {
+		t.Errorf("Setup did not fail for unknown database type")
+	}
+}
@@ -0,0 +1,164 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/fbufler/database-monitor/internal/service"
+	"github.com/miekg/dns"
+	"github.com/rs/zerolog/log"
+)
+
+// lookupTimeout bounds resolving the configured hostnames of a query, so a
+// slow upstream DNS server doesn't hold up the answer.
+const lookupTimeout = 2 * time.Second
+
+type Resolver interface {
+	Run(ctx context.Context)
+}
+
+// Source provides the cluster state the answers are built from.
+type Source interface {
+	Cluster(name string) (service.Cluster, bool)
+}
+
+type Config struct {
+	Port int
+	// Domain is the zone the resolver is authoritative for, e.g. "dbm".
+	Domain string
+	// TTL of every answer in seconds.
+	TTL int
+	// Hosts maps database identifiers to the host clients should connect to.
+	Hosts map[string]string
+}
+
+type ResolverImpl struct {
+	config Config
+	source Source
+	zone   string
+}
+
+func New(config Config, source Source) Resolver {
+	if config.Domain == "" {
+		config.Domain = "dbm"
+	}
+	return &ResolverImpl{
+		config: config,
+		source: source,
+		zone:   dns.Fqdn(strings.ToLower(config.Domain)),
+	}
+}
+
+// members resolves a query name of the form <role>.<cluster>.<zone> to the
+// database identifiers currently serving that role.
+func (r *ResolverImpl) members(name string) ([]string, bool) {
+	name = strings.ToLower(name)
+	if !dns.IsSubDomain(r.zone, name) {
+		return nil, false
+	}
+	labels := dns.SplitDomainName(strings.TrimSuffix(name, r.zone))
+	if len(labels) != 2 {
+		return nil, false
+	}
+	cluster, ok := r.source.Cluster(labels[1])
+	if !ok {
+		return nil, false
+	}
+	switch labels[0] {
+	case "primary":
+		// Same as /primary: writers must not be sent to a primary that
+		// failed its probes.
+		if cluster.Primary == "" || !cluster.PrimaryHealthy {
+			return []string{}, true
+		}
+		return []string{cluster.Primary}, true
+	case "replicas":
+		return cluster.Replicas, true
+	}
+	return nil, false
+}
+
+// addresses looks up the IP addresses of the given databases. Hosts
+// configured by name are resolved on every query so DNS changes upstream are
+// picked up.
+func (r *ResolverImpl) addresses(ctx context.Context, databases []string) []net.IP {
+	ips := []net.IP{}
+	for _, db := range databases {
+		host, ok := r.config.Hosts[db]
+		if !ok {
+			continue
+		}
+		if ip := net.ParseIP(host); ip != nil {
+			ips = append(ips, ip)
+			continue
+		}
+		resolved, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+		if err != nil {
+			log.Error().Msgf("resolving %s: %s", host, err)
+			continue
+		}
+		ips = append(ips, resolved...)
+	}
+	return ips
+}
+
+func (r *ResolverImpl) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	msg := new(dns.Msg)
+	msg.SetReply(req)
+	msg.Authoritative = true
+	if len(req.Question) != 1 {
+		msg.SetRcode(req, dns.RcodeFormatError)
+		w.WriteMsg(msg)
+		return
+	}
+	question := req.Question[0]
+	log.Debug().Msgf("DNS query for %s %s from %s", question.Name, dns.TypeToString[question.Qtype], w.RemoteAddr())
+	databases, ok := r.members(question.Name)
+	if !ok {
+		msg.SetRcode(req, dns.RcodeNameError)
+		w.WriteMsg(msg)
+		return
+	}
+	header := dns.RR_Header{
+		Name:   question.Name,
+		Class:  dns.ClassINET,
+		Rrtype: question.Qtype,
+		Ttl:    uint32(r.config.TTL),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+	for _, ip := range r.addresses(ctx, databases) {
+		ip4 := ip.To4()
+		switch {
+		case question.Qtype == dns.TypeA && ip4 != nil:
+			msg.Answer = append(msg.Answer, &dns.A{Hdr: header, A: ip4})
+		case question.Qtype == dns.TypeAAAA && ip4 == nil:
+			msg.Answer = append(msg.Answer, &dns.AAAA{Hdr: header, AAAA: ip})
+		}
+	}
+	w.WriteMsg(msg)
+}
+
+func (r *ResolverImpl) Run(ctx context.Context) {
+	addr := fmt.Sprintf(":%d", r.config.Port)
+	servers := []*dns.Server{
+		{Addr: addr, Net: "udp", Handler: r},
+		{Addr: addr, Net: "tcp", Handler: r},
+	}
+	log.Info().Msgf("Starting DNS resolver for %s on port %d", r.zone, r.config.Port)
+	for _, srv := range servers {
+		go func(srv *dns.Server) {
+			if err := srv.ListenAndServe(); err != nil {
+				log.Fatal().Msgf("resolver: %s", err)
+			}
+		}(srv)
+	}
+	<-ctx.Done()
+	log.Info().Msg("Shutting down DNS resolver")
+	for _, srv := range servers {
+		srv.Shutdown()
+	}
+}
@@ -0,0 +1,157 @@
+package resolver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/fbufler/database-monitor/internal/service"
+	"github.com/fbufler/database-monitor/internal/tester"
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/gorilla/mux"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSource map[string]service.Cluster
+
+func (f fakeSource) Cluster(name string) (service.Cluster, bool) {
+	cluster, ok := f[name]
+	return cluster, ok
+}
+
+func startResolver(t *testing.T) string {
+	r := New(Config{
+		Domain: "dbm",
+		TTL:    5,
+		Hosts: map[string]string{
+			"db1": "10.0.0.1",
+			"db2": "10.0.0.2",
+			"db3": "fd00::3",
+			"db4": "localhost",
+		},
+	}, fakeSource{
+		"main": {
+			Name:           "main",
+			Primary:        "db1",
+			PrimaryHealthy: true,
+			Replicas:       []string{"db2", "db3"},
+		},
+		"down": {
+			Name:     "down",
+			Primary:  "db1",
+			Replicas: []string{"db2"},
+		},
+		"broken": {
+			Name:     "broken",
+			Replicas: []string{},
+		},
+		"local": {
+			Name:           "local",
+			Primary:        "db4",
+			PrimaryHealthy: true,
+		},
+	})
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %s", err)
+	}
+	srv := &dns.Server{PacketConn: pc, Handler: r.(*ResolverImpl)}
+	started := make(chan struct{})
+	srv.NotifyStartedFunc = func() { close(started) }
+	go srv.ActivateAndServe()
+	<-started
+	t.Cleanup(func() {
+		srv.Shutdown()
+	})
+	return pc.LocalAddr().String()
+}
+
+func query(t *testing.T, addr string, name string, qtype uint16) *dns.Msg {
+	msg := new(dns.Msg)
+	msg.SetQuestion(name, qtype)
+	response, err := dns.Exchange(msg, addr)
+	if err != nil {
+		t.Fatalf("querying %s: %s", name, err)
+	}
+	return response
+}
+
+func answers(msg *dns.Msg) []string {
+	ips := []string{}
+	for _, rr := range msg.Answer {
+		switch rr := rr.(type) {
+		case *dns.A:
+			ips = append(ips, rr.A.String())
+		case *dns.AAAA:
+			ips = append(ips, rr.AAAA.String())
+		}
+	}
+	return ips
+}
+
+func TestResolverPrimary(t *testing.T) {
+	addr := startResolver(t)
+	response := query(t, addr, "primary.main.dbm.", dns.TypeA)
+	assert.Equal(t, dns.RcodeSuccess, response.Rcode)
+	assert.Equal(t, true, response.Authoritative)
+	assert.Equal(t, []string{"10.0.0.1"}, answers(response))
+	assert.Equal(t, uint32(5), response.Answer[0].Header().Ttl)
+}
+
+func TestResolverReplicas(t *testing.T) {
+	addr := startResolver(t)
+	assert.Equal(t, []string{"10.0.0.2"}, answers(query(t, addr, "replicas.main.dbm.", dns.TypeA)))
+	assert.Equal(t, []string{"fd00::3"}, answers(query(t, addr, "REPLICAS.Main.dbm.", dns.TypeAAAA)))
+}
+
+func TestResolverHostname(t *testing.T) {
+	addr := startResolver(t)
+	assert.Contains(t, answers(query(t, addr, "primary.local.dbm.", dns.TypeA)), "127.0.0.1")
+}
+
+func TestResolverNoPrimary(t *testing.T) {
+	addr := startResolver(t)
+	for _, name := range []string{"primary.broken.dbm.", "primary.down.dbm."} {
+		response := query(t, addr, name, dns.TypeA)
+		assert.Equal(t, dns.RcodeSuccess, response.Rcode, name)
+		assert.Empty(t, response.Answer, name)
+	}
+}
+
+func TestResolverStaleResult(t *testing.T) {
+	results := make(chan tester.Result)
+	s := service.New(service.Config{InvalidationTime: 1}, results, mux.NewRouter())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+	results <- tester.Result{
+		Database:    "db1",
+		Cluster:     "main",
+		Connectable: true,
+		Readable:    true,
+		Writable:    true,
+		Timestamp:   time.Now(),
+		Replication: database.Replication{Role: database.RolePrimary, Timeline: 1},
+	}
+	r := New(Config{Hosts: map[string]string{"db1": "10.0.0.1"}}, s).(*ResolverImpl)
+	assert.Eventually(t, func() bool {
+		databases, _ := r.members("primary.main.dbm.")
+		return len(databases) == 1
+	}, 500*time.Millisecond, 10*time.Millisecond)
+	// The tester stopped reporting, the last result outlives the
+	// invalidation time.
+	time.Sleep(1100 * time.Millisecond)
+	databases, ok := r.members("primary.main.dbm.")
+	assert.True(t, ok)
+	assert.Empty(t, databases)
+}
+
+func TestResolverUnknownName(t *testing.T) {
+	addr := startResolver(t)
+	for _, name := range []string{"primary.other.dbm.", "leader.main.dbm.", "primary.main.example.com.", "main.dbm."} {
+		response := query(t, addr, name, dns.TypeA)
+		assert.Equal(t, dns.RcodeNameError, response.Rcode, name)
+	}
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/fbufler/database-monitor/internal/tester"
+	"github.com/rs/zerolog/log"
+)
+
+type CapacityResponse struct {
+	Capacity map[string]*tester.Capacity `json:"capacity"`
+}
+
+func (s *ServiceImpl) getCapacityHandler(w http.ResponseWriter, r *http.Request) {
+	log.Debug().Msgf("Capacity requested from %s", r.RemoteAddr)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	capacity := make(map[string]*tester.Capacity)
+	for name, res := range s.resultsMap {
+		if res.Capacity != nil {
+			capacity[name] = res.Capacity
+		}
+	}
+	json.NewEncoder(w).Encode(CapacityResponse{
+		Capacity: capacity,
+	})
+}
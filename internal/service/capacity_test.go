@@ -0,0 +1,37 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fbufler/database-monitor/internal/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapacityHandler(t *testing.T) {
+	s := newClusterService()
+	reached := time.Now().Add(30 * 24 * time.Hour).UTC()
+	capacity := &tester.Capacity{
+		DatabaseBytes:     1 << 30,
+		GrowthBytesPerDay: 1 << 20,
+		BudgetBytes:       1 << 31,
+		BudgetReached:     &reached,
+		Since:             time.Now().Add(-time.Hour).UTC(),
+		Samples:           60,
+		Relations:         []tester.RelationCapacity{},
+	}
+	s.resultsMap["localhost:5432/postgres"] = tester.Result{
+		Database:  "localhost:5432/postgres",
+		Timestamp: time.Now(),
+		Capacity:  capacity,
+	}
+	s.routes()
+	response := httptest.NewRecorder()
+	s.router.ServeHTTP(response, httptest.NewRequest("GET", "/capacity", nil))
+	assert.Equal(t, 200, response.Code)
+	body := CapacityResponse{}
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+	assert.Equal(t, map[string]*tester.Capacity{"localhost:5432/postgres": capacity}, body.Capacity)
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/fbufler/database-monitor/internal/tester"
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+)
+
+type Cluster struct {
+	Name              string              `json:"name"`
+	Primary           string              `json:"primary"`
+	PrimaryHealthy    bool                `json:"primary_healthy"`
+	Primaries         []string            `json:"primaries"`
+	Timelines         []int64             `json:"timelines"`
+	SplitBrain        bool                `json:"split_brain"`
+	Replicas          []string            `json:"replicas"`
+	Members           []string            `json:"members"`
+	Healthy           int                 `json:"healthy"`
+	Unhealthy         int                 `json:"unhealthy"`
+	MaxReplayLag      time.Duration       `json:"max_replay_lag"`
+	MaxReplayLagBytes int64               `json:"max_replay_lag_bytes"`
+	SettingsDrift     []SettingDifference `json:"settings_drift,omitempty"`
+}
+
+type ClustersResponse struct {
+	Clusters map[string]Cluster `json:"clusters"`
+}
+
+// healthy reports whether a member passed every probe its role allows.
+// Standbys are read-only, so a failed write does not count against them.
+func healthy(res tester.Result) bool {
+	if !res.Connectable || !res.Readable {
+		return false
+	}
+	return res.Writable || res.Role == database.RoleStandby
+}
+
+// memberHealthy is healthy for results that are still current: a member
+// that stopped reporting can't be vouched for, neither over HTTP nor DNS.
+func (s *ServiceImpl) memberHealthy(res tester.Result) bool {
+	return healthy(res) && !s.stale(res)
+}
+
+// clusters aggregates the current results by cluster. The caller must hold
+// s.mu.
+func (s *ServiceImpl) clusters() map[string]Cluster {
+	members := make(map[string][]tester.Result)
+	for _, res := range s.resultsMap {
+		if res.Cluster == "" {
+			continue
+		}
+		members[res.Cluster] = append(members[res.Cluster], res)
+	}
+	clusters := make(map[string]Cluster)
+	for name, results := range members {
+		sort.Slice(results, func(i, j int) bool {
+			return results[i].Database < results[j].Database
+		})
+		cluster := Cluster{
+			Name:      name,
+			Primaries: []string{},
+			Timelines: []int64{},
+			Replicas:  []string{},
+			Members:   []string{},
+		}
+		timelines := make(map[int64]bool)
+		for _, res := range results {
+			cluster.Members = append(cluster.Members, res.Database)
+			if s.memberHealthy(res) {
+				cluster.Healthy++
+				if res.Role == database.RoleStandby {
+					cluster.Replicas = append(cluster.Replicas, res.Database)
+				}
+			} else {
+				cluster.Unhealthy++
+			}
+			if res.Role == database.RolePrimary {
+				cluster.Primaries = append(cluster.Primaries, res.Database)
+			}
+			if res.Timeline != 0 && !timelines[res.Timeline] {
+				timelines[res.Timeline] = true
+				cluster.Timelines = append(cluster.Timelines, res.Timeline)
+			}
+			if res.ReplayLag > cluster.MaxReplayLag {
+				cluster.MaxReplayLag = res.ReplayLag
+			}
+			if res.ReplayLagBytes > cluster.MaxReplayLagBytes {
+				cluster.MaxReplayLagBytes = res.ReplayLagBytes
+			}
+		}
+		sort.Slice(cluster.Timelines, func(i, j int) bool {
+			return cluster.Timelines[i] < cluster.Timelines[j]
+		})
+		// Diverging timelines are flagged, but a single primary stays the
+		// one to point clients at: a standby that hasn't followed a failover
+		// yet mustn't cause a write outage. Only with several members
+		// reporting themselves as primary there is none that is safe.
+		cluster.SplitBrain = len(cluster.Primaries) > 1 || len(cluster.Timelines) > 1
+		if len(cluster.Primaries) == 1 {
+			cluster.Primary = cluster.Primaries[0]
+			for _, res := range results {
+				if res.Database == cluster.Primary {
+					cluster.PrimaryHealthy = s.memberHealthy(res)
+				}
+			}
+		}
+		cluster.SettingsDrift = settingsDrift(results, s.config.IgnoredSettings)
+		clusters[name] = cluster
+	}
+	return clusters
+}
+
+// checkSplitBrain logs every cluster entering or leaving split brain. The
+// caller must hold s.mu.
+func (s *ServiceImpl) checkSplitBrain(clusters map[string]Cluster) {
+	for name, cluster := range clusters {
+		if cluster.SplitBrain && !s.splitBrain[name] {
+			log.Error().
+				Str("event", "split_brain").
+				Str("cluster", name).
+				Strs("primaries", cluster.Primaries).
+				Ints64("timelines", cluster.Timelines).
+				Msgf("split brain detected in cluster %s", name)
+		}
+		if !cluster.SplitBrain && s.splitBrain[name] {
+			log.Info().
+				Str("event", "split_brain_resolved").
+				Str("cluster", name).
+				Msgf("split brain resolved in cluster %s", name)
+		}
+		s.splitBrain[name] = cluster.SplitBrain
+	}
+	for name := range s.splitBrain {
+		if _, ok := clusters[name]; !ok {
+			delete(s.splitBrain, name)
+		}
+	}
+}
+
+// splitBrainClusters returns the sorted names of all clusters currently in
+// split brain. The caller must hold s.mu.
+func (s *ServiceImpl) splitBrainClusters() []string {
+	names := []string{}
+	for name, splitBrain := range s.splitBrain {
+		if splitBrain {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Cluster returns the current state of the named cluster.
+func (s *ServiceImpl) Cluster(name string) (Cluster, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cluster, ok := s.clusters()[name]
+	return cluster, ok
+}
+
+func (s *ServiceImpl) getClustersHandler(w http.ResponseWriter, r *http.Request) {
+	log.Debug().Msgf("Clusters requested from %s", r.RemoteAddr)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	json.NewEncoder(w).Encode(ClustersResponse{
+		Clusters: s.clusters(),
+	})
+}
+
+func (s *ServiceImpl) getClusterHandler(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	log.Debug().Msgf("Cluster %s requested from %s", name, r.RemoteAddr)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cluster, ok := s.clusters()[name]
+	if !ok {
+		http.Error(w, "cluster not found", http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(cluster)
+}
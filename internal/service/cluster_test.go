@@ -0,0 +1,199 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fbufler/database-monitor/internal/tester"
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func newClusterService() *ServiceImpl {
+	s := New(Config{Port: 8080, InvalidationTime: 60}, make(chan tester.Result), mux.NewRouter()).(*ServiceImpl)
+	now := time.Now()
+	s.resultsMap = map[string]tester.Result{
+		"db1": {
+			Database:    "db1",
+			Cluster:     "main",
+			Connectable: true,
+			Readable:    true,
+			Writable:    true,
+			Timestamp:   now,
+			Replication: database.Replication{Role: database.RolePrimary, Timeline: 2},
+		},
+		"db2": {
+			Database:    "db2",
+			Cluster:     "main",
+			Connectable: true,
+			Readable:    true,
+			Timestamp:   now,
+			Replication: database.Replication{Role: database.RoleStandby, Timeline: 2, ReplayLag: 2 * time.Second, ReplayLagBytes: 2048},
+		},
+		"db3": {
+			Database:    "db3",
+			Cluster:     "main",
+			Timestamp:   now,
+			Replication: database.Replication{Role: database.RoleStandby},
+		},
+		"db4": {
+			Database:    "db4",
+			Connectable: true,
+			Readable:    true,
+			Writable:    true,
+			Timestamp:   now,
+		},
+	}
+	return s
+}
+
+func TestClusters(t *testing.T) {
+	s := newClusterService()
+	clusters := s.clusters()
+	assert.Len(t, clusters, 1)
+	assert.Equal(t, Cluster{
+		Name:              "main",
+		Primary:           "db1",
+		PrimaryHealthy:    true,
+		Primaries:         []string{"db1"},
+		Timelines:         []int64{2},
+		Replicas:          []string{"db2"},
+		Members:           []string{"db1", "db2", "db3"},
+		Healthy:           2,
+		Unhealthy:         1,
+		MaxReplayLag:      2 * time.Second,
+		MaxReplayLagBytes: 2048,
+	}, clusters["main"])
+}
+
+func TestClustersUnhealthyPrimary(t *testing.T) {
+	s := newClusterService()
+	res := s.resultsMap["db1"]
+	res.Writable = false
+	s.resultsMap["db1"] = res
+	cluster := s.clusters()["main"]
+	assert.Equal(t, "db1", cluster.Primary)
+	assert.Equal(t, false, cluster.PrimaryHealthy)
+}
+
+func TestClustersStaleMembers(t *testing.T) {
+	s := newClusterService()
+	for _, name := range []string{"db1", "db2"} {
+		res := s.resultsMap[name]
+		res.Timestamp = time.Now().Add(-2 * time.Minute)
+		s.resultsMap[name] = res
+	}
+	cluster := s.clusters()["main"]
+	assert.Equal(t, "db1", cluster.Primary)
+	assert.Equal(t, false, cluster.PrimaryHealthy)
+	assert.Equal(t, []string{}, cluster.Replicas)
+	assert.Equal(t, 0, cluster.Healthy)
+}
+
+func TestGetClustersHandler(t *testing.T) {
+	s := newClusterService()
+	response := httptest.NewRecorder()
+	s.getClustersHandler(response, httptest.NewRequest("GET", "/clusters", nil))
+	assert.Equal(t, 200, response.Code)
+	body := ClustersResponse{}
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+	assert.Equal(t, "db1", body.Clusters["main"].Primary)
+}
+
+func TestGetClusterHandler(t *testing.T) {
+	s := newClusterService()
+	request := mux.SetURLVars(httptest.NewRequest("GET", "/clusters/main", nil), map[string]string{"name": "main"})
+	response := httptest.NewRecorder()
+	s.getClusterHandler(response, request)
+	assert.Equal(t, 200, response.Code)
+	cluster := Cluster{}
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&cluster))
+	assert.Equal(t, "main", cluster.Name)
+	assert.Equal(t, 2, cluster.Healthy)
+}
+
+func TestGetClusterHandlerNotFound(t *testing.T) {
+	s := newClusterService()
+	request := mux.SetURLVars(httptest.NewRequest("GET", "/clusters/other", nil), map[string]string{"name": "other"})
+	response := httptest.NewRecorder()
+	s.getClusterHandler(response, request)
+	assert.Equal(t, 404, response.Code)
+}
+
+func TestClustersSplitBrainPrimaries(t *testing.T) {
+	s := newClusterService()
+	res := s.resultsMap["db2"]
+	res.Role = database.RolePrimary
+	s.resultsMap["db2"] = res
+	cluster := s.clusters()["main"]
+	assert.Equal(t, true, cluster.SplitBrain)
+	assert.Equal(t, "", cluster.Primary)
+	assert.Equal(t, []string{"db1", "db2"}, cluster.Primaries)
+}
+
+func TestClustersSplitBrainTimelines(t *testing.T) {
+	s := newClusterService()
+	res := s.resultsMap["db2"]
+	res.Timeline = 3
+	s.resultsMap["db2"] = res
+	cluster := s.clusters()["main"]
+	assert.Equal(t, true, cluster.SplitBrain)
+	assert.Equal(t, []int64{2, 3}, cluster.Timelines)
+	// The only primary is still served.
+	assert.Equal(t, "db1", cluster.Primary)
+	assert.Equal(t, true, cluster.PrimaryHealthy)
+}
+
+func TestCheckSplitBrain(t *testing.T) {
+	var buf bytes.Buffer
+	log.Logger = log.Output(&buf)
+	s := newClusterService()
+	s.checkSplitBrain(s.clusters())
+	assert.NotContains(t, buf.String(), "split_brain")
+	assert.Equal(t, []string{}, s.splitBrainClusters())
+
+	res := s.resultsMap["db2"]
+	res.Role = database.RolePrimary
+	s.resultsMap["db2"] = res
+	s.checkSplitBrain(s.clusters())
+	assert.Contains(t, buf.String(), `"event":"split_brain"`)
+	assert.Contains(t, buf.String(), "split brain detected in cluster main")
+	assert.Equal(t, []string{"main"}, s.splitBrainClusters())
+
+	buf.Reset()
+	s.checkSplitBrain(s.clusters())
+	assert.NotContains(t, buf.String(), "split brain detected")
+
+	res.Role = database.RoleStandby
+	s.resultsMap["db2"] = res
+	s.checkSplitBrain(s.clusters())
+	assert.Contains(t, buf.String(), `"event":"split_brain_resolved"`)
+	assert.Equal(t, []string{}, s.splitBrainClusters())
+}
+
+func TestGetResultsHandlerSplitBrain(t *testing.T) {
+	s := newClusterService()
+	res := s.resultsMap["db2"]
+	res.Role = database.RolePrimary
+	s.resultsMap["db2"] = res
+	s.checkSplitBrain(s.clusters())
+	response := httptest.NewRecorder()
+	s.getResultsHandler(response, httptest.NewRequest("GET", "/results", nil))
+	body := Response{}
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+	assert.Equal(t, []string{"main"}, body.SplitBrain)
+}
+
+func TestCluster(t *testing.T) {
+	s := newClusterService()
+	cluster, ok := s.Cluster("main")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "db1", cluster.Primary)
+	_, ok = s.Cluster("other")
+	assert.Equal(t, false, ok)
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/fbufler/database-monitor/internal/tester"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+)
+
+// databaseSection picks the part of a result served by one of the
+// /databases/{database}/... endpoints. It reports false when the section
+// hasn't been collected.
+type databaseSection func(res tester.Result) (any, bool)
+
+// writeDatabaseSection answers the per database endpoints from the latest
+// result of the database, 404 when there is none or it lacks the section.
+func (s *ServiceImpl) writeDatabaseSection(w http.ResponseWriter, r *http.Request, section databaseSection) {
+	name := mux.Vars(r)["database"]
+	log.Debug().Msgf("%s requested from %s", r.URL.Path, r.RemoteAddr)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res, ok := s.resultsMap[name]
+	if !ok {
+		http.Error(w, "no recent result for database", http.StatusNotFound)
+		return
+	}
+	body, ok := section(res)
+	if !ok {
+		http.Error(w, "not collected for database", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+)
+
+// maxFailoverEvents limits the history kept per cluster.
+const maxFailoverEvents = 100
+
+type FailoverEvent struct {
+	Timestamp   time.Time `json:"timestamp"`
+	OldPrimary  string    `json:"old_primary"`
+	NewPrimary  string    `json:"new_primary"`
+	OldTimeline int64     `json:"old_timeline"`
+	NewTimeline int64     `json:"new_timeline"`
+	// Downtime is the time between the last probe that could write to the
+	// old primary and the probe that found the new one.
+	Downtime time.Duration `json:"downtime"`
+}
+
+type EventsResponse struct {
+	Events []FailoverEvent `json:"events"`
+}
+
+// topology is the last known primary of a cluster and its failover history.
+type topology struct {
+	primary      string
+	timeline     int64
+	lastWritable time.Time
+	events       []FailoverEvent
+}
+
+// checkFailover records a failover event for every cluster whose primary or
+// primary timeline changed since the last result. Clusters without a single
+// primary keep their last known topology until one is elected. The caller
+// must hold s.mu.
+func (s *ServiceImpl) checkFailover(clusters map[string]Cluster) {
+	for name, cluster := range clusters {
+		state, ok := s.topology[name]
+		if !ok {
+			state = &topology{events: []FailoverEvent{}}
+			s.topology[name] = state
+		}
+		if cluster.Primary == "" {
+			continue
+		}
+		primary := s.resultsMap[cluster.Primary]
+		if state.primary != "" && (state.primary != primary.Database || state.timeline != primary.Timeline) {
+			event := FailoverEvent{
+				Timestamp:   primary.Timestamp,
+				OldPrimary:  state.primary,
+				NewPrimary:  primary.Database,
+				OldTimeline: state.timeline,
+				NewTimeline: primary.Timeline,
+			}
+			if !state.lastWritable.IsZero() && primary.Timestamp.After(state.lastWritable) {
+				event.Downtime = primary.Timestamp.Sub(state.lastWritable)
+			}
+			log.Warn().
+				Str("event", "failover").
+				Str("cluster", name).
+				Str("old_primary", event.OldPrimary).
+				Str("new_primary", event.NewPrimary).
+				Int64("old_timeline", event.OldTimeline).
+				Int64("new_timeline", event.NewTimeline).
+				Dur("downtime", event.Downtime).
+				Msgf("failover in cluster %s from %s to %s", name, event.OldPrimary, event.NewPrimary)
+			state.events = append(state.events, event)
+			if len(state.events) > maxFailoverEvents {
+				state.events = state.events[len(state.events)-maxFailoverEvents:]
+			}
+		}
+		state.primary = primary.Database
+		state.timeline = primary.Timeline
+		if primary.Writable && primary.Timestamp.After(state.lastWritable) {
+			state.lastWritable = primary.Timestamp
+		}
+	}
+}
+
+func (s *ServiceImpl) getClusterEventsHandler(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	log.Debug().Msgf("Events of cluster %s requested from %s", name, r.RemoteAddr)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	state, ok := s.topology[name]
+	if !ok {
+		http.Error(w, "cluster not found", http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(EventsResponse{
+		Events: state.events,
+	})
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckFailover(t *testing.T) {
+	var buf bytes.Buffer
+	log.Logger = log.Output(&buf)
+	s := newClusterService()
+	s.checkFailover(s.clusters())
+	assert.Equal(t, "db1", s.topology["main"].primary)
+	assert.Empty(t, s.topology["main"].events)
+	lastWritable := s.resultsMap["db1"].Timestamp
+
+	// db1 goes down, nobody is primary for a while.
+	delete(s.resultsMap, "db1")
+	s.checkFailover(s.clusters())
+	assert.Empty(t, s.topology["main"].events)
+
+	// db2 gets promoted onto a new timeline.
+	promoted := s.resultsMap["db2"]
+	promoted.Role = database.RolePrimary
+	promoted.Timeline = 3
+	promoted.Writable = true
+	promoted.Timestamp = lastWritable.Add(10 * time.Second)
+	s.resultsMap["db2"] = promoted
+	s.checkFailover(s.clusters())
+	assert.Equal(t, []FailoverEvent{
+		{
+			Timestamp:   promoted.Timestamp,
+			OldPrimary:  "db1",
+			NewPrimary:  "db2",
+			OldTimeline: 2,
+			NewTimeline: 3,
+			Downtime:    10 * time.Second,
+		},
+	}, s.topology["main"].events)
+	assert.Contains(t, buf.String(), `"event":"failover"`)
+
+	// Further probes of the same primary don't add events.
+	s.checkFailover(s.clusters())
+	assert.Len(t, s.topology["main"].events, 1)
+}
+
+func TestCheckFailoverTimeline(t *testing.T) {
+	s := newClusterService()
+	s.checkFailover(s.clusters())
+	primary := s.resultsMap["db1"]
+	primary.Timeline = 3
+	s.resultsMap["db1"] = primary
+	standby := s.resultsMap["db2"]
+	standby.Timeline = 3
+	s.resultsMap["db2"] = standby
+	s.checkFailover(s.clusters())
+	assert.Len(t, s.topology["main"].events, 1)
+	assert.Equal(t, "db1", s.topology["main"].events[0].OldPrimary)
+	assert.Equal(t, "db1", s.topology["main"].events[0].NewPrimary)
+	assert.Equal(t, int64(3), s.topology["main"].events[0].NewTimeline)
+}
+
+func TestGetClusterEventsHandler(t *testing.T) {
+	s := newClusterService()
+	s.checkFailover(s.clusters())
+	request := mux.SetURLVars(httptest.NewRequest("GET", "/clusters/main/events", nil), map[string]string{"name": "main"})
+	response := httptest.NewRecorder()
+	s.getClusterEventsHandler(response, request)
+	assert.Equal(t, 200, response.Code)
+	assert.Equal(t, "{\"events\":[]}\n", response.Body.String())
+
+	request = mux.SetURLVars(httptest.NewRequest("GET", "/clusters/other/events", nil), map[string]string{"name": "other"})
+	response = httptest.NewRecorder()
+	s.getClusterEventsHandler(response, request)
+	assert.Equal(t, 404, response.Code)
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/fbufler/database-monitor/internal/tester"
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+)
+
+// roleCheck decides whether a database currently serves the requested role.
+// It returns the reason when it doesn't.
+type roleCheck func(res tester.Result) (bool, string)
+
+// writeRoleResponse answers load balancer health checks: 200 when the latest
+// result of the database passes check and is within the invalidation time,
+// 503 otherwise. The result itself is returned as body either way.
+func (s *ServiceImpl) writeRoleResponse(w http.ResponseWriter, r *http.Request, check roleCheck) {
+	name := mux.Vars(r)["database"]
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res, ok := s.resultsMap[name]
+	if !ok {
+		http.Error(w, "no recent result for database", http.StatusServiceUnavailable)
+		return
+	}
+	status := http.StatusOK
+	passed, reason := check(res)
+	if s.stale(res) {
+		// The node stopped reporting, whatever it last said no longer holds.
+		passed, reason = false, fmt.Sprintf("result older than %ds", s.config.InvalidationTime)
+	}
+	if !passed {
+		log.Debug().Msgf("%s: Role check for %s failed: %s", name, r.URL.Path, reason)
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (s *ServiceImpl) getPrimaryHandler(w http.ResponseWriter, r *http.Request) {
+	s.writeRoleResponse(w, r, func(res tester.Result) (bool, string) {
+		if res.Role != database.RolePrimary {
+			return false, "not a primary"
+		}
+		if !healthy(res) {
+			return false, "unhealthy"
+		}
+		if res.Cluster != "" && len(s.clusters()[res.Cluster].Primaries) > 1 {
+			return false, "cluster has several primaries"
+		}
+		return true, ""
+	})
+}
+
+func (s *ServiceImpl) getReplicaHandler(w http.ResponseWriter, r *http.Request) {
+	var maxLag time.Duration
+	if value := r.URL.Query().Get("max_lag"); value != "" {
+		var err error
+		maxLag, err = time.ParseDuration(value)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid max_lag: %s", err), http.StatusBadRequest)
+			return
+		}
+	}
+	s.writeRoleResponse(w, r, func(res tester.Result) (bool, string) {
+		if res.Role != database.RoleStandby {
+			return false, "not a replica"
+		}
+		if !healthy(res) {
+			return false, "unhealthy"
+		}
+		if maxLag > 0 && res.ReplayLag > maxLag {
+			return false, fmt.Sprintf("replay lag %s exceeds %s", res.ReplayLag, maxLag)
+		}
+		return true, ""
+	})
+}
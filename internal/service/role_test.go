@@ -0,0 +1,88 @@
+package service
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fbufler/database-monitor/internal/tester"
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveRole(s *ServiceImpl, method string, target string) int {
+	response := httptest.NewRecorder()
+	s.router.ServeHTTP(response, httptest.NewRequest(method, target, nil))
+	return response.Code
+}
+
+func TestRoleHandlers(t *testing.T) {
+	s := newClusterService()
+	s.resultsMap["localhost:5432/postgres"] = tester.Result{
+		Database:    "localhost:5432/postgres",
+		Connectable: true,
+		Readable:    true,
+		Timestamp:   time.Now(),
+		Replication: database.Replication{Role: database.RoleStandby, ReplayLag: 15 * time.Second},
+	}
+	s.routes()
+	tests := []struct {
+		method string
+		target string
+		want   int
+	}{
+		{"GET", "/primary/db1", 200},
+		{"OPTIONS", "/primary/db1", 200},
+		{"HEAD", "/primary/db1", 200},
+		{"GET", "/primary/db2", 503},
+		{"GET", "/primary/db4", 503},
+		{"GET", "/primary/unknown", 503},
+		{"GET", "/replica/db1", 503},
+		{"GET", "/replica/db2", 200},
+		{"GET", "/replica/db2?max_lag=10s", 200},
+		{"GET", "/replica/db2?max_lag=1s", 503},
+		{"GET", "/replica/db2?max_lag=soon", 400},
+		{"GET", "/replica/db3", 503},
+		{"GET", "/replica/localhost:5432/postgres", 200},
+		{"GET", "/replica/localhost:5432/postgres?max_lag=10s", 503},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			assert.Equal(t, tt.want, serveRole(s, tt.method, tt.target))
+		})
+	}
+}
+
+func TestPrimaryHandlerSplitBrain(t *testing.T) {
+	s := newClusterService()
+	res := s.resultsMap["db2"]
+	res.Role = database.RolePrimary
+	res.Writable = true
+	s.resultsMap["db2"] = res
+	s.checkSplitBrain(s.clusters())
+	s.routes()
+	assert.Equal(t, 503, serveRole(s, "GET", "/primary/db1"))
+	assert.Equal(t, 503, serveRole(s, "GET", "/primary/db2"))
+}
+
+func TestPrimaryHandlerDivergingTimelines(t *testing.T) {
+	s := newClusterService()
+	res := s.resultsMap["db2"]
+	res.Timeline = 1
+	s.resultsMap["db2"] = res
+	s.checkSplitBrain(s.clusters())
+	s.routes()
+	assert.Equal(t, 200, serveRole(s, "GET", "/primary/db1"))
+}
+
+func TestRoleHandlersStale(t *testing.T) {
+	s := newClusterService()
+	for _, name := range []string{"db1", "db2"} {
+		res := s.resultsMap[name]
+		res.Timestamp = time.Now().Add(-2 * time.Minute)
+		s.resultsMap[name] = res
+	}
+	s.routes()
+	assert.Equal(t, 503, serveRole(s, "GET", "/primary/db1"))
+	assert.Equal(t, 503, serveRole(s, "GET", "/replica/db2"))
+}
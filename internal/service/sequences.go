@@ -0,0 +1,25 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/fbufler/database-monitor/internal/tester"
+	"github.com/fbufler/database-monitor/pkg/database"
+)
+
+type SequencesResponse struct {
+	Database string `json:"database"`
+	*database.Sequences
+}
+
+func (s *ServiceImpl) getSequencesHandler(w http.ResponseWriter, r *http.Request) {
+	s.writeDatabaseSection(w, r, func(res tester.Result) (any, bool) {
+		if res.Sequences == nil {
+			return nil, false
+		}
+		return SequencesResponse{
+			Database:  res.Database,
+			Sequences: res.Sequences,
+		}, true
+	})
+}
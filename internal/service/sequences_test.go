@@ -0,0 +1,39 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fbufler/database-monitor/internal/tester"
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSequencesHandler(t *testing.T) {
+	s := newClusterService()
+	sequences := &database.Sequences{
+		Timestamp: time.Now().UTC(),
+		Threshold: 0.8,
+		Sequences: []database.Sequence{
+			{Name: "public.orders_id_seq", Table: "public.orders", Column: "id", ColumnType: "integer", LastValue: 2000000000, MaxValue: 2147483647, Used: 0.93},
+		},
+	}
+	s.resultsMap["localhost:5432/postgres"] = tester.Result{
+		Database:  "localhost:5432/postgres",
+		Timestamp: time.Now(),
+		Sequences: sequences,
+	}
+	s.routes()
+	response := httptest.NewRecorder()
+	s.router.ServeHTTP(response, httptest.NewRequest("GET", "/databases/localhost:5432/postgres/sequences", nil))
+	assert.Equal(t, 200, response.Code)
+	body := SequencesResponse{}
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+	assert.Equal(t, sequences, body.Sequences)
+
+	response = httptest.NewRecorder()
+	s.router.ServeHTTP(response, httptest.NewRequest("GET", "/databases/db1/sequences", nil))
+	assert.Equal(t, 404, response.Code)
+}
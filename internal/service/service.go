@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/fbufler/database-monitor/internal/tester"
@@ -14,27 +15,37 @@ import (
 
 type Service interface {
 	Run(ctx context.Context)
+	Cluster(name string) (Cluster, bool)
 }
 
 type Config struct {
 	Port             int
 	InvalidationTime int
+	// IgnoredSettings are left out when comparing the settings of cluster
+	// members, next to the ones that describe the node itself.
+	IgnoredSettings []string
 }
 
 type Response struct {
-	Results map[string]tester.Result `json:"results"`
+	Results    map[string]tester.Result `json:"results"`
+	SplitBrain []string                 `json:"split_brain,omitempty"`
 }
 
 type ServiceImpl struct {
 	config     Config
 	results    chan tester.Result
 	router     *mux.Router
+	mu         sync.RWMutex
 	resultsMap map[string]tester.Result
+	splitBrain map[string]bool
+	topology   map[string]*topology
 }
 
 func New(config Config, results chan tester.Result, router *mux.Router) Service {
 	return &ServiceImpl{
 		resultsMap: make(map[string]tester.Result),
+		splitBrain: make(map[string]bool),
+		topology:   make(map[string]*topology),
 		config:     config,
 		results:    results,
 		router:     router,
@@ -46,31 +57,60 @@ func (s *ServiceImpl) collectResults(ctx context.Context) {
 	for {
 		select {
 		case res := <-s.results:
+			s.mu.Lock()
 			s.resultsMap[res.Database] = res
 		case <-ctx.Done():
 			return
 		}
 		for _, res := range s.resultsMap {
-			if time.Since(res.Timestamp) > time.Duration(s.config.InvalidationTime)*time.Second {
+			if s.stale(res) {
 				delete(s.resultsMap, res.Database)
 			}
 		}
+		clusters := s.clusters()
+		s.checkSplitBrain(clusters)
+		s.checkFailover(clusters)
+		s.mu.Unlock()
 	}
 }
 
+// stale tells whether res is older than the invalidation time.
+func (s *ServiceImpl) stale(res tester.Result) bool {
+	return time.Since(res.Timestamp) > time.Duration(s.config.InvalidationTime)*time.Second
+}
+
 func (s *ServiceImpl) getResultsHandler(w http.ResponseWriter, r *http.Request) {
 	log.Debug().Msgf("Result requested from %s", r.RemoteAddr)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	json.NewEncoder(w).Encode(Response{
-		Results: s.resultsMap,
+		Results:    s.resultsMap,
+		SplitBrain: s.splitBrainClusters(),
 	})
 }
 
+func (s *ServiceImpl) routes() {
+	s.router.HandleFunc("/results", s.getResultsHandler).Methods("GET")
+	s.router.HandleFunc("/capacity", s.getCapacityHandler).Methods("GET")
+	s.router.HandleFunc("/clusters", s.getClustersHandler).Methods("GET")
+	s.router.HandleFunc("/clusters/{name}", s.getClusterHandler).Methods("GET")
+	s.router.HandleFunc("/clusters/{name}/events", s.getClusterEventsHandler).Methods("GET")
+	// Load balancers probe with GET, HEAD or OPTIONS; database identifiers
+	// contain slashes.
+	s.router.HandleFunc("/primary/{database:.+}", s.getPrimaryHandler).Methods("GET", "HEAD", "OPTIONS")
+	s.router.HandleFunc("/replica/{database:.+}", s.getReplicaHandler).Methods("GET", "HEAD", "OPTIONS")
+	s.router.HandleFunc("/databases/{database:.+}/sessions", s.getSessionsHandler).Methods("GET")
+	s.router.HandleFunc("/databases/{database:.+}/vacuum", s.getVacuumHandler).Methods("GET")
+	s.router.HandleFunc("/databases/{database:.+}/statements", s.getStatementsHandler).Methods("GET")
+	s.router.HandleFunc("/databases/{database:.+}/sequences", s.getSequencesHandler).Methods("GET")
+}
+
 func (s *ServiceImpl) Run(ctx context.Context) {
 	srv := &http.Server{
 		Addr: fmt.Sprintf(":%d", s.config.Port),
 	}
 	go s.collectResults(ctx)
-	s.router.HandleFunc("/results", s.getResultsHandler).Methods("GET")
+	s.routes()
 	srv.Handler = s.router
 	log.Info().Msgf("Starting service on port %d", s.config.Port)
 	go func() {
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/fbufler/database-monitor/internal/tester"
+	"github.com/fbufler/database-monitor/pkg/database"
+)
+
+type SessionsResponse struct {
+	Database  string             `json:"database"`
+	Timestamp time.Time          `json:"timestamp"`
+	Sessions  []database.Session `json:"sessions"`
+}
+
+func (s *ServiceImpl) getSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	s.writeDatabaseSection(w, r, func(res tester.Result) (any, bool) {
+		sessions := res.Sessions
+		if sessions == nil {
+			sessions = []database.Session{}
+		}
+		return SessionsResponse{
+			Database:  res.Database,
+			Timestamp: res.Timestamp,
+			Sessions:  sessions,
+		}, true
+	})
+}
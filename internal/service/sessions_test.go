@@ -0,0 +1,42 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fbufler/database-monitor/internal/tester"
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionsHandler(t *testing.T) {
+	s := newClusterService()
+	sessions := []database.Session{
+		{PID: 42, User: "app", Application: "api", State: "active", Duration: time.Minute, Query: "SELECT pg_sleep(60)"},
+	}
+	s.resultsMap["localhost:5432/postgres"] = tester.Result{
+		Database:  "localhost:5432/postgres",
+		Timestamp: time.Now(),
+		Sessions:  sessions,
+	}
+	s.routes()
+	response := httptest.NewRecorder()
+	s.router.ServeHTTP(response, httptest.NewRequest("GET", "/databases/localhost:5432/postgres/sessions", nil))
+	assert.Equal(t, 200, response.Code)
+	body := SessionsResponse{}
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+	assert.Equal(t, "localhost:5432/postgres", body.Database)
+	assert.Equal(t, sessions, body.Sessions)
+
+	response = httptest.NewRecorder()
+	s.router.ServeHTTP(response, httptest.NewRequest("GET", "/databases/db1/sessions", nil))
+	assert.Equal(t, 200, response.Code)
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+	assert.Equal(t, []database.Session{}, body.Sessions)
+
+	response = httptest.NewRecorder()
+	s.router.ServeHTTP(response, httptest.NewRequest("GET", "/databases/unknown/sessions", nil))
+	assert.Equal(t, 404, response.Code)
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/fbufler/database-monitor/internal/tester"
+)
+
+// nodeSettings are expected to differ between members of a cluster: they
+// describe the node itself, its role, its files or dbm's own session.
+var nodeSettings = map[string]bool{
+	"application_name":              true,
+	"archive_cleanup_command":       true,
+	"archive_command":               true,
+	"archive_library":               true,
+	"archive_mode":                  true,
+	"cluster_name":                  true,
+	"config_file":                   true,
+	"data_directory":                true,
+	"default_transaction_read_only": true,
+	"external_pid_file":             true,
+	"hba_file":                      true,
+	"ident_file":                    true,
+	"in_hot_standby":                true,
+	"krb_server_keyfile":            true,
+	"listen_addresses":              true,
+	"port":                          true,
+	"primary_conninfo":              true,
+	"primary_slot_name":             true,
+	"promote_trigger_file":          true,
+	"recovery_end_command":          true,
+	"recovery_min_apply_delay":      true,
+	"restore_command":               true,
+	"ssl_ca_file":                   true,
+	"ssl_cert_file":                 true,
+	"ssl_crl_dir":                   true,
+	"ssl_crl_file":                  true,
+	"ssl_dh_params_file":            true,
+	"ssl_key_file":                  true,
+	"synchronous_standby_names":     true,
+	"transaction_read_only":         true,
+	"unix_socket_directories":       true,
+}
+
+// SettingDifference is a parameter set differently across the members of a
+// cluster, with the value of every member.
+type SettingDifference struct {
+	Name   string            `json:"name"`
+	Values map[string]string `json:"values"`
+}
+
+// settingsDrift compares the settings of the members that reported them,
+// leaving out nodeSettings and the ignored ones.
+func settingsDrift(results []tester.Result, ignored []string) []SettingDifference {
+	members := []tester.Result{}
+	names := make(map[string]bool)
+	for _, res := range results {
+		if res.Settings == nil {
+			continue
+		}
+		members = append(members, res)
+		for name := range res.Settings.Values {
+			names[name] = true
+		}
+	}
+	if len(members) < 2 {
+		return nil
+	}
+	differences := []SettingDifference{}
+	for name := range names {
+		if nodeSettings[name] || slices.Contains(ignored, name) {
+			continue
+		}
+		values := make(map[string]string)
+		distinct := make(map[string]bool)
+		for _, res := range members {
+			value := res.Settings.Values[name]
+			values[res.Database] = value
+			distinct[value] = true
+		}
+		if len(distinct) > 1 {
+			differences = append(differences, SettingDifference{Name: name, Values: values})
+		}
+	}
+	if len(differences) == 0 {
+		return nil
+	}
+	sort.Slice(differences, func(i, j int) bool {
+		return differences[i].Name < differences[j].Name
+	})
+	return differences
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClustersSettingsDrift(t *testing.T) {
+	s := newClusterService()
+	settings := map[string]map[string]string{
+		"db1": {"max_connections": "200", "work_mem": "4096", "primary_conninfo": "", "synchronous_standby_names": "db2", "wal_level": "replica", "hot_standby_feedback": "off"},
+		"db2": {"max_connections": "100", "work_mem": "8192", "primary_conninfo": "host=db1", "synchronous_standby_names": "", "wal_level": "replica", "hot_standby_feedback": "on"},
+	}
+	for name, values := range settings {
+		res := s.resultsMap[name]
+		res.Settings = &database.Settings{Values: values}
+		s.resultsMap[name] = res
+	}
+	assert.Equal(t, []SettingDifference{
+		{Name: "hot_standby_feedback", Values: map[string]string{"db1": "off", "db2": "on"}},
+		{Name: "max_connections", Values: map[string]string{"db1": "200", "db2": "100"}},
+		{Name: "work_mem", Values: map[string]string{"db1": "4096", "db2": "8192"}},
+	}, s.clusters()["main"].SettingsDrift)
+	s.config.IgnoredSettings = []string{"hot_standby_feedback", "work_mem"}
+	assert.Equal(t, []SettingDifference{
+		{Name: "max_connections", Values: map[string]string{"db1": "200", "db2": "100"}},
+	}, s.clusters()["main"].SettingsDrift)
+}
+
+func TestClustersSettingsNoDrift(t *testing.T) {
+	s := newClusterService()
+	res := s.resultsMap["db1"]
+	res.Settings = &database.Settings{Values: map[string]string{"max_connections": "100"}}
+	s.resultsMap["db1"] = res
+	// A single member reporting settings has nothing to compare with.
+	assert.Nil(t, s.clusters()["main"].SettingsDrift)
+	res = s.resultsMap["db2"]
+	res.Settings = &database.Settings{Values: map[string]string{"max_connections": "100"}}
+	s.resultsMap["db2"] = res
+	assert.Nil(t, s.clusters()["main"].SettingsDrift)
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/fbufler/database-monitor/internal/tester"
+)
+
+type StatementsResponse struct {
+	Database   string                  `json:"database"`
+	Timestamp  time.Time               `json:"timestamp"`
+	Statements []tester.StatementDelta `json:"statements"`
+}
+
+func (s *ServiceImpl) getStatementsHandler(w http.ResponseWriter, r *http.Request) {
+	s.writeDatabaseSection(w, r, func(res tester.Result) (any, bool) {
+		if res.Statements == nil {
+			return nil, false
+		}
+		return StatementsResponse{
+			Database:   res.Database,
+			Timestamp:  res.Timestamp,
+			Statements: res.Statements,
+		}, true
+	})
+}
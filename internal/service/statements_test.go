@@ -0,0 +1,35 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fbufler/database-monitor/internal/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatementsHandler(t *testing.T) {
+	s := newClusterService()
+	statements := []tester.StatementDelta{
+		{QueryID: 42, Query: "SELECT 1", Calls: 10, TotalTime: time.Second, MeanTime: 100 * time.Millisecond, BaselineMeanTime: 10 * time.Millisecond, Flagged: true},
+	}
+	s.resultsMap["localhost:5432/postgres"] = tester.Result{
+		Database:             "localhost:5432/postgres",
+		Timestamp:            time.Now(),
+		Statements:           statements,
+		StatementRegressions: 1,
+	}
+	s.routes()
+	response := httptest.NewRecorder()
+	s.router.ServeHTTP(response, httptest.NewRequest("GET", "/databases/localhost:5432/postgres/statements", nil))
+	assert.Equal(t, 200, response.Code)
+	body := StatementsResponse{}
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+	assert.Equal(t, statements, body.Statements)
+
+	response = httptest.NewRecorder()
+	s.router.ServeHTTP(response, httptest.NewRequest("GET", "/databases/db1/statements", nil))
+	assert.Equal(t, 404, response.Code)
+}
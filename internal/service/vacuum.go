@@ -0,0 +1,25 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/fbufler/database-monitor/internal/tester"
+	"github.com/fbufler/database-monitor/pkg/database"
+)
+
+type VacuumResponse struct {
+	Database string `json:"database"`
+	*database.Vacuum
+}
+
+func (s *ServiceImpl) getVacuumHandler(w http.ResponseWriter, r *http.Request) {
+	s.writeDatabaseSection(w, r, func(res tester.Result) (any, bool) {
+		if res.Vacuum == nil {
+			return nil, false
+		}
+		return VacuumResponse{
+			Database: res.Database,
+			Vacuum:   res.Vacuum,
+		}, true
+	})
+}
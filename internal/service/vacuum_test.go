@@ -0,0 +1,38 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fbufler/database-monitor/internal/tester"
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVacuumHandler(t *testing.T) {
+	s := newClusterService()
+	vacuum := &database.Vacuum{
+		Timestamp: time.Now().UTC(),
+		Tables:    []database.TableVacuum{{Table: "orders", LiveTuples: 100, DeadTuples: 900, DeadRatio: 0.9, Flagged: true}},
+		Bloat:     []database.TableBloat{{Table: "orders", SizeBytes: 8192, BloatBytes: 4096, BloatPercent: 50}},
+	}
+	s.resultsMap["localhost:5432/postgres"] = tester.Result{
+		Database:  "localhost:5432/postgres",
+		Timestamp: time.Now(),
+		Vacuum:    vacuum,
+	}
+	s.routes()
+	response := httptest.NewRecorder()
+	s.router.ServeHTTP(response, httptest.NewRequest("GET", "/databases/localhost:5432/postgres/vacuum", nil))
+	assert.Equal(t, 200, response.Code)
+	body := VacuumResponse{}
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+	assert.Equal(t, "localhost:5432/postgres", body.Database)
+	assert.Equal(t, vacuum, body.Vacuum)
+
+	response = httptest.NewRecorder()
+	s.router.ServeHTTP(response, httptest.NewRequest("GET", "/databases/db1/vacuum", nil))
+	assert.Equal(t, 404, response.Code)
+}
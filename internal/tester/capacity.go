@@ -0,0 +1,166 @@
+package tester
+
+import (
+	"context"
+	"time"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/rs/zerolog/log"
+)
+
+// Sizes are kept at most once per capacitySampleInterval for up to
+// maxCapacitySamples samples, i.e. a week.
+const (
+	capacitySampleInterval = time.Minute
+	maxCapacitySamples     = 7 * 24 * 60
+)
+
+// Capacity is the size of a database, its growth fitted linearly over the
+// recorded samples and, with a size budget configured, when it is expected
+// to reach it. BudgetReached is left out while the database doesn't grow.
+type Capacity struct {
+	DatabaseBytes     int64              `json:"database_bytes"`
+	GrowthBytesPerDay float64            `json:"growth_bytes_per_day"`
+	BudgetBytes       int64              `json:"budget_bytes,omitempty"`
+	BudgetReached     *time.Time         `json:"budget_reached,omitempty"`
+	Since             time.Time          `json:"since"`
+	Samples           int                `json:"samples"`
+	Relations         []RelationCapacity `json:"relations"`
+}
+
+// RelationCapacity is the size and growth of one of the largest relations.
+type RelationCapacity struct {
+	database.RelationSize
+	GrowthBytesPerDay float64 `json:"growth_bytes_per_day"`
+}
+
+type sizeSample struct {
+	timestamp time.Time
+	bytes     int64
+}
+
+// sizeHistory holds the sizes of a database and its largest relations over
+// time. read is when the sizes were last read and latest the capacity derived
+// from them, which is reported until the next sample is due.
+type sizeHistory struct {
+	database  []sizeSample
+	relations map[database.RelationSize][]sizeSample
+	read      time.Time
+	latest    *Capacity
+}
+
+// relationKey identifies a relation independent of its size.
+func relationKey(relation database.RelationSize) database.RelationSize {
+	relation.Bytes = 0
+	return relation
+}
+
+func (h *sizeHistory) add(sizes *database.Sizes) {
+	if n := len(h.database); n > 0 && sizes.Timestamp.Sub(h.database[n-1].timestamp) < capacitySampleInterval {
+		return
+	}
+	h.database = appendSample(h.database, sizeSample{sizes.Timestamp, sizes.DatabaseBytes})
+	for _, relation := range sizes.Relations {
+		key := relationKey(relation)
+		h.relations[key] = appendSample(h.relations[key], sizeSample{sizes.Timestamp, relation.Bytes})
+	}
+	oldest := h.database[0].timestamp
+	for key, samples := range h.relations {
+		for len(samples) > 0 && samples[0].timestamp.Before(oldest) {
+			samples = samples[1:]
+		}
+		if len(samples) == 0 {
+			delete(h.relations, key)
+			continue
+		}
+		h.relations[key] = samples
+	}
+}
+
+func appendSample(samples []sizeSample, sample sizeSample) []sizeSample {
+	samples = append(samples, sample)
+	if len(samples) > maxCapacitySamples {
+		samples = samples[len(samples)-maxCapacitySamples:]
+	}
+	return samples
+}
+
+// growthPerSecond is the slope of a least squares fit through samples.
+func growthPerSecond(samples []sizeSample) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+	n := float64(len(samples))
+	var sumX, sumY, sumXY, sumXX float64
+	for _, sample := range samples {
+		x := sample.timestamp.Sub(samples[0].timestamp).Seconds()
+		y := float64(sample.bytes)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denominator
+}
+
+func (h *sizeHistory) capacity(sizes *database.Sizes) *Capacity {
+	growth := growthPerSecond(h.database)
+	capacity := &Capacity{
+		DatabaseBytes:     sizes.DatabaseBytes,
+		GrowthBytesPerDay: growth * (24 * time.Hour).Seconds(),
+		BudgetBytes:       sizes.BudgetBytes,
+		Since:             h.database[0].timestamp,
+		Samples:           len(h.database),
+		Relations:         []RelationCapacity{},
+	}
+	if sizes.BudgetBytes > 0 {
+		switch {
+		case sizes.DatabaseBytes >= sizes.BudgetBytes:
+			capacity.BudgetReached = &sizes.Timestamp
+		case growth > 0:
+			reached := sizes.Timestamp.Add(time.Duration(float64(sizes.BudgetBytes-sizes.DatabaseBytes) / growth * float64(time.Second)))
+			capacity.BudgetReached = &reached
+		}
+	}
+	for _, relation := range sizes.Relations {
+		capacity.Relations = append(capacity.Relations, RelationCapacity{
+			RelationSize:      relation,
+			GrowthBytesPerDay: growthPerSecond(h.relations[relationKey(relation)]) * (24 * time.Hour).Seconds(),
+		})
+	}
+	return capacity
+}
+
+func (p *TesterImpl) readSizes(db database.Database, ctx context.Context, result *Result) {
+	reporter, ok := db.(database.SizeReporter)
+	if !ok {
+		return
+	}
+	p.mu.Lock()
+	history, ok := p.sizes[result.Database]
+	if !ok {
+		history = &sizeHistory{relations: make(map[database.RelationSize][]sizeSample)}
+		p.sizes[result.Database] = history
+	}
+	if time.Since(history.read) < capacitySampleInterval {
+		result.Capacity = history.latest
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Unlock()
+	sizes, err := reporter.Sizes(ctx)
+	if err != nil {
+		log.Error().Msgf("reading sizes of %s: %s", result.Database, err)
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	history.add(sizes)
+	history.read = time.Now()
+	history.latest = history.capacity(sizes)
+	result.Capacity = history.latest
+}
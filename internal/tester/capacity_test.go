@@ -0,0 +1,78 @@
+package tester
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGrowthPerSecond(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, 0.0, growthPerSecond(nil))
+	assert.Equal(t, 0.0, growthPerSecond([]sizeSample{{now, 100}}))
+	assert.InDelta(t, 10.0, growthPerSecond([]sizeSample{
+		{now, 1000},
+		{now.Add(10 * time.Second), 1090},
+		{now.Add(20 * time.Second), 1210},
+		{now.Add(30 * time.Second), 1300},
+	}), 0.5)
+}
+
+func TestSizeHistory(t *testing.T) {
+	history := &sizeHistory{relations: make(map[database.RelationSize][]sizeSample)}
+	now := time.Now()
+	sizes := func(offset time.Duration, bytes int64) *database.Sizes {
+		return &database.Sizes{
+			Timestamp:     now.Add(offset),
+			DatabaseBytes: bytes,
+			BudgetBytes:   2000,
+			Relations:     []database.RelationSize{{Name: "public.events", Kind: "table", Bytes: bytes / 2}},
+		}
+	}
+	history.add(sizes(0, 1000))
+	capacity := history.capacity(sizes(0, 1000))
+	assert.Equal(t, 0.0, capacity.GrowthBytesPerDay)
+	assert.Nil(t, capacity.BudgetReached)
+	// Samples closer than capacitySampleInterval are skipped.
+	history.add(sizes(time.Second, 1001))
+	assert.Len(t, history.database, 1)
+	last := sizes(time.Hour, 1100)
+	history.add(sizes(30*time.Minute, 1050))
+	history.add(last)
+	capacity = history.capacity(last)
+	assert.Equal(t, 3, capacity.Samples)
+	assert.InDelta(t, 2400.0, capacity.GrowthBytesPerDay, 0.001)
+	assert.InDelta(t, 1200.0, capacity.Relations[0].GrowthBytesPerDay, 0.001)
+	assert.WithinDuration(t, now.Add(10*time.Hour), *capacity.BudgetReached, time.Second)
+	over := sizes(2*time.Hour, 2500)
+	history.add(over)
+	assert.Equal(t, over.Timestamp, *history.capacity(over).BudgetReached)
+}
+
+type mockSizeDatabase struct {
+	*database.MockDatabase
+	*database.MockSizeReporter
+}
+
+func TestReadSizes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := mockSizeDatabase{
+		MockDatabase:     database.NewMockDatabase(ctrl),
+		MockSizeReporter: database.NewMockSizeReporter(ctrl),
+	}
+	tester := New(Config{}).(*TesterImpl)
+	mockDatabase.MockSizeReporter.EXPECT().Sizes(gomock.Any()).Return(&database.Sizes{Timestamp: time.Now(), DatabaseBytes: 1 << 20}, nil)
+	result := Result{Database: "test"}
+	tester.readSizes(mockDatabase, context.Background(), &result)
+	assert.Equal(t, int64(1<<20), result.Capacity.DatabaseBytes)
+	assert.Equal(t, 1, result.Capacity.Samples)
+	// The sizes aren't read again until the next sample is due.
+	next := Result{Database: "test"}
+	tester.readSizes(mockDatabase, context.Background(), &next)
+	assert.Equal(t, result.Capacity, next.Capacity)
+}
@@ -3,6 +3,7 @@ package tester
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/fbufler/database-monitor/pkg/database"
@@ -10,14 +11,25 @@ import (
 )
 
 type TesterImpl struct {
-	results chan Result
-	config  Config
+	results    chan Result
+	config     Config
+	mu         sync.Mutex
+	vacuum     map[string]*database.Vacuum
+	stats      map[string]*database.Stats
+	statements map[string]*statementHistory
+	sizes      map[string]*sizeHistory
+	sequences  map[string]*database.Sequences
 }
 
 func New(config Config) Tester {
 	return &TesterImpl{
-		results: make(chan Result),
-		config:  config,
+		results:    make(chan Result),
+		config:     config,
+		vacuum:     make(map[string]*database.Vacuum),
+		stats:      make(map[string]*database.Stats),
+		statements: make(map[string]*statementHistory),
+		sizes:      make(map[string]*sizeHistory),
+		sequences:  make(map[string]*database.Sequences),
 	}
 }
 
@@ -29,6 +41,8 @@ func (p *TesterImpl) Run(ctx context.Context) chan Result {
 func (p *TesterImpl) run(ctx context.Context) {
 	log.Info().Msg("Starting postgres tester")
 	log.Debug().Msg("Starting database tests")
+	go p.schedule(ctx, p.vacuumInterval(), p.collectVacuum)
+	go p.schedule(ctx, p.sequencesInterval(), p.collectSequences)
 	for {
 		select {
 		case <-ctx.Done():
@@ -57,6 +71,9 @@ func (p *TesterImpl) runDatabaseTest(db database.Database, ctx context.Context)
 		Readable:    false,
 		Timestamp:   time.Now(),
 	}
+	result.Cluster = p.config.Clusters[result.Database]
+	result.Vacuum = p.latestVacuum(result.Database)
+	result.Sequences = p.latestSequences(result.Database)
 	connectionTime := time.Now()
 	err := db.Connect()
 	select {
@@ -72,34 +89,61 @@ func (p *TesterImpl) runDatabaseTest(db database.Database, ctx context.Context)
 	result.ConnectionTime = time.Since(connectionTime)
 	defer db.Close()
 	result.Connectable = true
+	// Every reporter gets a deadline of its own, so a hung catalog query or
+	// a dead connection only costs its section and the probes still run.
+	for _, read := range []func(database.Database, context.Context, *Result){
+		p.readReplication,
+		p.readConnections,
+		p.readSessions,
+		p.readLocks,
+		p.readWraparound,
+		p.readStats,
+		p.readStatements,
+		p.readSizes,
+		p.readBackup,
+		p.readSettings,
+	} {
+		readCtx, cancel := context.WithTimeout(ctx, p.testTimeout())
+		read(db, readCtx, &result)
+		cancel()
+	}
+	// So do the probes: one queued behind a lock would otherwise never
+	// return, and never get its failure explained.
 	readTime := time.Now()
-	err = db.TestRead(ctx)
+	readCtx, cancel := context.WithTimeout(ctx, p.testTimeout())
+	err = db.TestRead(readCtx)
+	cancel()
 	select {
 	case <-ctx.Done():
 		return
 	default:
 	}
 	if err != nil {
-		log.Error().Msgf("reading from %s: %s", result.Database, err)
+		log.Error().Msgf("reading from %s: %s%s", result.Database, err, p.explainFailure(db, ctx, &result))
 		p.results <- result
 		return
 	}
 	result.ReadTime = time.Since(readTime)
 	result.Readable = true
 	writeTime := time.Now()
-	err = db.TestWrite(ctx)
+	writeCtx, cancel := context.WithTimeout(ctx, p.testTimeout())
+	err = db.TestWrite(writeCtx)
+	cancel()
 	select {
 	case <-ctx.Done():
 		return
 	default:
 	}
 	if err != nil {
-		log.Error().Msgf("writing to %s: %s", result.Database, err)
+		log.Error().Msgf("writing to %s: %s%s", result.Database, err, p.explainFailure(db, ctx, &result))
 		p.results <- result
 		return
 	}
 	result.WriteTime = time.Since(writeTime)
 	result.Writable = true
+	if result.Role == database.RolePrimary && result.Cluster != "" {
+		result.Propagation = p.testPropagation(db, result.Cluster, ctx)
+	}
 	select {
 	case <-ctx.Done():
 		return
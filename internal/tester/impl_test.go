@@ -58,12 +58,15 @@ func TestRun(t *testing.T) {
 	})
 	mockDatabase.EXPECT().Identifier().Return("test")
 	mockDatabase.EXPECT().Connect().Return(nil)
-	mockDatabase.EXPECT().TestRead(ctx).Return(nil)
-	mockDatabase.EXPECT().TestWrite(ctx).Return(nil)
-	mockDatabase.EXPECT().Close().Return(nil)
-	go postgresTester.Run(ctx)
-	result := <-postgresTester.(*TesterImpl).results
+	mockDatabase.EXPECT().TestRead(gomock.Any()).Return(nil)
+	mockDatabase.EXPECT().TestWrite(gomock.Any()).Return(nil)
+	// Once after the probe and once when the tester shuts down.
+	mockDatabase.EXPECT().Close().Return(nil).Times(2)
+	results := postgresTester.Run(ctx)
+	result := <-results
 	cancel()
+	for range results {
+	}
 	assert.Equal(t, "test", result.Database)
 	assert.Equal(t, true, result.Connectable)
 	assert.Equal(t, true, result.Readable)
@@ -84,8 +87,8 @@ func TestRunDatabaseTest(t *testing.T) {
 	})
 	mockDatabase.EXPECT().Identifier().Return("test")
 	mockDatabase.EXPECT().Connect().Return(nil)
-	mockDatabase.EXPECT().TestRead(ctx).Return(nil)
-	mockDatabase.EXPECT().TestWrite(ctx).Return(nil)
+	mockDatabase.EXPECT().TestRead(gomock.Any()).Return(nil)
+	mockDatabase.EXPECT().TestWrite(gomock.Any()).Return(nil)
 	mockDatabase.EXPECT().Close().Return(nil)
 	go postgresTester.(*TesterImpl).runDatabaseTest(mockDatabase, ctx)
 	result := <-postgresTester.(*TesterImpl).results
@@ -133,7 +136,7 @@ func TestRunDatabaseTestReadError(t *testing.T) {
 	})
 	mockDatabase.EXPECT().Identifier().Return("test")
 	mockDatabase.EXPECT().Connect().Return(nil)
-	mockDatabase.EXPECT().TestRead(ctx).Return(errors.New("Read error"))
+	mockDatabase.EXPECT().TestRead(gomock.Any()).Return(errors.New("Read error"))
 	mockDatabase.EXPECT().Close().Return(nil)
 	go postgresTester.(*TesterImpl).runDatabaseTest(mockDatabase, ctx)
 	result := <-postgresTester.(*TesterImpl).results
@@ -158,8 +161,8 @@ func TestRunDatabaseTestWriteError(t *testing.T) {
 	})
 	mockDatabase.EXPECT().Identifier().Return("test")
 	mockDatabase.EXPECT().Connect().Return(nil)
-	mockDatabase.EXPECT().TestRead(ctx).Return(nil)
-	mockDatabase.EXPECT().TestWrite(ctx).Return(errors.New("Write error"))
+	mockDatabase.EXPECT().TestRead(gomock.Any()).Return(nil)
+	mockDatabase.EXPECT().TestWrite(gomock.Any()).Return(errors.New("Write error"))
 	mockDatabase.EXPECT().Close().Return(nil)
 	go postgresTester.(*TesterImpl).runDatabaseTest(mockDatabase, ctx)
 	result := <-postgresTester.(*TesterImpl).results
@@ -169,3 +172,103 @@ func TestRunDatabaseTestWriteError(t *testing.T) {
 	assert.Equal(t, true, result.Readable)
 	assert.Equal(t, false, result.Writable)
 }
+
+func TestRunDatabaseTestHungReporter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := mockReplicationDatabase{
+		MockDatabase:            database.NewMockDatabase(ctrl),
+		MockReplicationReporter: database.NewMockReplicationReporter(ctrl),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	postgresTester := New(Config{
+		TestTimeout:  1,
+		TestInterval: 1,
+		Databases: []database.Database{
+			mockDatabase,
+		},
+	})
+	mockDatabase.MockDatabase.EXPECT().Identifier().Return("test")
+	mockDatabase.MockDatabase.EXPECT().Connect().Return(nil)
+	mockDatabase.MockReplicationReporter.EXPECT().Replication(gomock.Any()).DoAndReturn(func(ctx context.Context) (*database.Replication, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	mockDatabase.MockDatabase.EXPECT().TestRead(gomock.Any()).Return(nil)
+	mockDatabase.MockDatabase.EXPECT().TestWrite(gomock.Any()).Return(nil)
+	mockDatabase.MockDatabase.EXPECT().Close().Return(nil)
+	go postgresTester.(*TesterImpl).runDatabaseTest(mockDatabase, ctx)
+	result := <-postgresTester.(*TesterImpl).results
+	cancel()
+	assert.Equal(t, database.Role(""), result.Role)
+	assert.Equal(t, true, result.Readable)
+	assert.Equal(t, true, result.Writable)
+}
+
+func TestRunDatabaseTestCluster(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabase(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	postgresTester := New(Config{
+		TestTimeout:  1,
+		TestInterval: 1,
+		Databases: []database.Database{
+			mockDatabase,
+		},
+		Clusters: map[string]string{"test": "main"},
+	})
+	mockDatabase.EXPECT().Identifier().Return("test")
+	mockDatabase.EXPECT().Connect().Return(errors.New("Connect error"))
+	go postgresTester.(*TesterImpl).runDatabaseTest(mockDatabase, ctx)
+	result := <-postgresTester.(*TesterImpl).results
+	cancel()
+	assert.Equal(t, "main", result.Cluster)
+}
+
+type mockLockDatabase struct {
+	*database.MockDatabase
+	*database.MockLockReporter
+}
+
+func TestRunDatabaseTestLocks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := mockLockDatabase{
+		MockDatabase:     database.NewMockDatabase(ctrl),
+		MockLockReporter: database.NewMockLockReporter(ctrl),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	postgresTester := New(Config{
+		TestTimeout:  1,
+		TestInterval: 1,
+		Databases: []database.Database{
+			mockDatabase,
+		},
+	})
+	blocking := []database.Blocker{
+		{
+			PID:     10,
+			Locks:   []database.Lock{{Type: "relation", Mode: "AccessExclusiveLock", Relation: "test", Granted: true}},
+			Waiters: []database.Blocker{{PID: 11}},
+		},
+	}
+	mockDatabase.MockDatabase.EXPECT().Identifier().Return("test")
+	mockDatabase.MockDatabase.EXPECT().Connect().Return(nil)
+	gomock.InOrder(
+		mockDatabase.MockLockReporter.EXPECT().Locks(gomock.Any()).Return([]database.Blocker{}, nil),
+		mockDatabase.MockLockReporter.EXPECT().Locks(gomock.Any()).Return(blocking, nil),
+	)
+	mockDatabase.MockDatabase.EXPECT().TestRead(gomock.Any()).Return(nil)
+	// The write is queued behind the lock until the probe's deadline.
+	mockDatabase.MockDatabase.EXPECT().TestWrite(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	mockDatabase.MockDatabase.EXPECT().Close().Return(nil)
+	go postgresTester.(*TesterImpl).runDatabaseTest(mockDatabase, ctx)
+	result := <-postgresTester.(*TesterImpl).results
+	cancel()
+	assert.Equal(t, false, result.Writable)
+	assert.Equal(t, blocking, result.Blocking)
+}
@@ -0,0 +1,51 @@
+package tester
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/rs/zerolog/log"
+)
+
+// readLocks attaches the blocking trees of db to result.
+func (p *TesterImpl) readLocks(db database.Database, ctx context.Context, result *Result) {
+	reporter, ok := db.(database.LockReporter)
+	if !ok {
+		return
+	}
+	lockCtx, cancel := context.WithTimeout(ctx, p.testTimeout())
+	defer cancel()
+	blocking, err := reporter.Locks(lockCtx)
+	if err != nil {
+		log.Error().Msgf("reading locks of %s: %s", result.Database, err)
+		return
+	}
+	result.Blocking = blocking
+}
+
+// explainFailure re-reads the locks after a failed probe, so the result shows
+// what the probe may have been queued behind, and describes the root
+// blockers.
+func (p *TesterImpl) explainFailure(db database.Database, ctx context.Context, result *Result) string {
+	p.readLocks(db, ctx, result)
+	reasons := []string{}
+	for _, root := range result.Blocking {
+		locks := []string{}
+		for _, lock := range root.Locks {
+			if lock.Relation != "" {
+				locks = append(locks, fmt.Sprintf("%s on %s", lock.Mode, lock.Relation))
+			}
+		}
+		reason := fmt.Sprintf("pid %d blocks %d sessions", root.PID, root.Waiting())
+		if len(locks) > 0 {
+			reason = fmt.Sprintf("%s holding %s", reason, strings.Join(locks, ", "))
+		}
+		reasons = append(reasons, reason)
+	}
+	if len(reasons) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", strings.Join(reasons, "; "))
+}
@@ -0,0 +1,41 @@
+package tester
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExplainFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := mockLockDatabase{
+		MockDatabase:     database.NewMockDatabase(ctrl),
+		MockLockReporter: database.NewMockLockReporter(ctrl),
+	}
+	tester := New(Config{TestTimeout: 1}).(*TesterImpl)
+	mockDatabase.MockLockReporter.EXPECT().Locks(gomock.Any()).Return([]database.Blocker{
+		{
+			PID: 10,
+			Locks: []database.Lock{
+				{Type: "relation", Mode: "AccessExclusiveLock", Relation: "test", Granted: true},
+				{Type: "relation", Mode: "AccessExclusiveLock", Relation: "orders", Granted: true},
+			},
+			Waiters: []database.Blocker{{PID: 11, Waiters: []database.Blocker{{PID: 12}}}},
+		},
+		{PID: 20, Locks: []database.Lock{{Type: "transactionid", Mode: "ExclusiveLock", Granted: true}}, Waiters: []database.Blocker{{PID: 21}}},
+	}, nil)
+	result := Result{}
+	assert.Equal(t, " (pid 10 blocks 2 sessions holding AccessExclusiveLock on test, AccessExclusiveLock on orders; pid 20 blocks 1 sessions)", tester.explainFailure(mockDatabase, context.Background(), &result))
+	assert.Len(t, result.Blocking, 2)
+}
+
+func TestExplainFailureWithoutLocks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	tester := New(Config{}).(*TesterImpl)
+	assert.Equal(t, "", tester.explainFailure(database.NewMockDatabase(ctrl), context.Background(), &Result{}))
+}
@@ -0,0 +1,76 @@
+package tester
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultTestTimeout = 5
+
+func (p *TesterImpl) testTimeout() time.Duration {
+	if p.config.TestTimeout == 0 {
+		return defaultTestTimeout * time.Second
+	}
+	return time.Duration(p.config.TestTimeout) * time.Second
+}
+
+// testPropagation writes a token on the primary and waits for it on every
+// other member of the cluster. Members are polled directly, so cascaded
+// replicas the primary doesn't know about are covered as well.
+func (p *TesterImpl) testPropagation(primary database.Database, cluster string, ctx context.Context) []Propagation {
+	prober, ok := primary.(database.PropagationProber)
+	if !ok {
+		return nil
+	}
+	replicas := []database.Database{}
+	for _, db := range p.config.Databases {
+		if db == primary || p.config.Clusters[db.Identifier()] != cluster {
+			continue
+		}
+		if _, ok := db.(database.PropagationProber); ok {
+			replicas = append(replicas, db)
+		}
+	}
+	if len(replicas) == 0 {
+		return nil
+	}
+	token := fmt.Sprintf("dbm-%d", time.Now().UnixNano())
+	err := prober.WriteToken(ctx, token)
+	if err != nil {
+		log.Error().Msgf("writing propagation token to %s: %s", primary.Identifier(), err)
+		return nil
+	}
+	written := time.Now()
+	defer func() {
+		err := prober.DeleteToken(ctx, token)
+		if err != nil {
+			log.Error().Msgf("deleting propagation token from %s: %s", primary.Identifier(), err)
+		}
+	}()
+	waitCtx, cancel := context.WithTimeout(ctx, p.testTimeout())
+	defer cancel()
+	propagation := make([]Propagation, len(replicas))
+	var wg sync.WaitGroup
+	for i, replica := range replicas {
+		wg.Add(1)
+		go func(i int, replica database.Database) {
+			defer wg.Done()
+			propagation[i].Database = replica.Identifier()
+			err := replica.(database.PropagationProber).WaitForToken(waitCtx, token)
+			if err != nil {
+				log.Error().Msgf("waiting for propagation token on %s: %s", propagation[i].Database, err)
+				propagation[i].Error = err.Error()
+				return
+			}
+			propagation[i].Visible = true
+			propagation[i].Latency = time.Since(written)
+		}(i, replica)
+	}
+	wg.Wait()
+	return propagation
+}
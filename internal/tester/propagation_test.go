@@ -0,0 +1,92 @@
+package tester
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+type mockPropagationDatabase struct {
+	*database.MockDatabase
+	*database.MockPropagationProber
+}
+
+func newMockPropagationDatabase(ctrl *gomock.Controller, identifier string) mockPropagationDatabase {
+	db := mockPropagationDatabase{
+		MockDatabase:          database.NewMockDatabase(ctrl),
+		MockPropagationProber: database.NewMockPropagationProber(ctrl),
+	}
+	db.MockDatabase.EXPECT().Identifier().Return(identifier).AnyTimes()
+	return db
+}
+
+func TestTestPropagation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	primary := newMockPropagationDatabase(ctrl, "primary")
+	replica := newMockPropagationDatabase(ctrl, "replica")
+	stuck := newMockPropagationDatabase(ctrl, "stuck")
+	other := newMockPropagationDatabase(ctrl, "other")
+	plain := database.NewMockDatabase(ctrl)
+	plain.EXPECT().Identifier().Return("plain").AnyTimes()
+	ctx := context.Background()
+	tester := New(Config{
+		Databases: []database.Database{primary, replica, stuck, other, plain},
+		Clusters: map[string]string{
+			"primary": "main",
+			"replica": "main",
+			"stuck":   "main",
+			"plain":   "main",
+			"other":   "other",
+		},
+	}).(*TesterImpl)
+	var token string
+	primary.MockPropagationProber.EXPECT().WriteToken(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, written string) error {
+		token = written
+		return nil
+	})
+	replica.MockPropagationProber.EXPECT().WaitForToken(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, waited string) error {
+		assert.Equal(t, token, waited)
+		return nil
+	})
+	stuck.MockPropagationProber.EXPECT().WaitForToken(gomock.Any(), gomock.Any()).Return(context.DeadlineExceeded)
+	primary.MockPropagationProber.EXPECT().DeleteToken(ctx, gomock.Any()).Return(nil)
+	propagation := tester.testPropagation(primary, "main", ctx)
+	assert.Len(t, propagation, 2)
+	assert.Equal(t, "replica", propagation[0].Database)
+	assert.Equal(t, true, propagation[0].Visible)
+	assert.Greater(t, propagation[0].Latency, time.Duration(0))
+	assert.Equal(t, "stuck", propagation[1].Database)
+	assert.Equal(t, false, propagation[1].Visible)
+	assert.Equal(t, context.DeadlineExceeded.Error(), propagation[1].Error)
+}
+
+func TestTestPropagationWriteError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	primary := newMockPropagationDatabase(ctrl, "primary")
+	replica := newMockPropagationDatabase(ctrl, "replica")
+	ctx := context.Background()
+	tester := New(Config{
+		Databases: []database.Database{primary, replica},
+		Clusters:  map[string]string{"primary": "main", "replica": "main"},
+	}).(*TesterImpl)
+	primary.MockPropagationProber.EXPECT().WriteToken(ctx, gomock.Any()).Return(errors.New("Write error"))
+	assert.Nil(t, tester.testPropagation(primary, "main", ctx))
+}
+
+func TestTestPropagationNoReplicas(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	primary := newMockPropagationDatabase(ctrl, "primary")
+	tester := New(Config{
+		Databases: []database.Database{primary},
+		Clusters:  map[string]string{"primary": "main"},
+	}).(*TesterImpl)
+	assert.Nil(t, tester.testPropagation(primary, "main", context.Background()))
+}
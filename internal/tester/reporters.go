@@ -0,0 +1,91 @@
+package tester
+
+import (
+	"context"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/rs/zerolog/log"
+)
+
+// readReplication attaches the replication role and state of db to result.
+func (p *TesterImpl) readReplication(db database.Database, ctx context.Context, result *Result) {
+	reporter, ok := db.(database.ReplicationReporter)
+	if !ok {
+		return
+	}
+	replication, err := reporter.Replication(ctx)
+	if err != nil {
+		log.Error().Msgf("detecting replication role of %s: %s", result.Database, err)
+		return
+	}
+	result.Replication = *replication
+}
+
+func (p *TesterImpl) readConnections(db database.Database, ctx context.Context, result *Result) {
+	reporter, ok := db.(database.ConnectionReporter)
+	if !ok {
+		return
+	}
+	connections, err := reporter.Connections(ctx)
+	if err != nil {
+		log.Error().Msgf("reading connections of %s: %s", result.Database, err)
+		return
+	}
+	result.Connections = connections
+}
+
+func (p *TesterImpl) readSessions(db database.Database, ctx context.Context, result *Result) {
+	reporter, ok := db.(database.SessionReporter)
+	if !ok {
+		return
+	}
+	sessions, err := reporter.Sessions(ctx)
+	if err != nil {
+		log.Error().Msgf("reading sessions of %s: %s", result.Database, err)
+		return
+	}
+	result.Sessions = sessions
+	result.SessionSummary = summarizeSessions(sessions)
+}
+
+func (p *TesterImpl) readWraparound(db database.Database, ctx context.Context, result *Result) {
+	reporter, ok := db.(database.WraparoundReporter)
+	if !ok {
+		return
+	}
+	wraparound, err := reporter.Wraparound(ctx)
+	if err != nil {
+		log.Error().Msgf("reading wraparound ages of %s: %s", result.Database, err)
+		return
+	}
+	result.Wraparound = wraparound
+}
+
+func (p *TesterImpl) readBackup(db database.Database, ctx context.Context, result *Result) {
+	reporter, ok := db.(database.BackupReporter)
+	if !ok {
+		return
+	}
+	backup, err := reporter.Backup(ctx)
+	if err != nil {
+		log.Error().Msgf("checking backups of %s: %s", result.Database, err)
+		return
+	}
+	if backup != nil {
+		result.BackedUp = &backup.Healthy
+		result.Backup = backup
+	}
+}
+
+func (p *TesterImpl) readSettings(db database.Database, ctx context.Context, result *Result) {
+	reporter, ok := db.(database.SettingsReporter)
+	if !ok {
+		return
+	}
+	settings, err := reporter.Settings(ctx)
+	if err != nil {
+		log.Error().Msgf("reading settings of %s: %s", result.Database, err)
+		return
+	}
+	result.Settings = settings
+}
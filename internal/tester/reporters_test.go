@@ -0,0 +1,156 @@
+package tester
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type mockReplicationDatabase struct {
+	*database.MockDatabase
+	*database.MockReplicationReporter
+}
+
+func TestReadReplication(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := mockReplicationDatabase{
+		MockDatabase:            database.NewMockDatabase(ctrl),
+		MockReplicationReporter: database.NewMockReplicationReporter(ctrl),
+	}
+	tester := New(Config{}).(*TesterImpl)
+	gomock.InOrder(
+		mockDatabase.MockReplicationReporter.EXPECT().Replication(gomock.Any()).Return(&database.Replication{
+			Role:     database.RoleStandby,
+			Timeline: 2,
+			Upstream: "primary:5432",
+		}, nil),
+		mockDatabase.MockReplicationReporter.EXPECT().Replication(gomock.Any()).Return(nil, errors.New("Replication error")),
+	)
+	result := Result{Database: "test"}
+	tester.readReplication(mockDatabase, context.Background(), &result)
+	assert.Equal(t, database.RoleStandby, result.Role)
+	assert.Equal(t, int64(2), result.Timeline)
+	assert.Equal(t, "primary:5432", result.Upstream)
+	result = Result{Database: "test"}
+	tester.readReplication(mockDatabase, context.Background(), &result)
+	assert.Equal(t, database.Role(""), result.Role)
+}
+
+type mockConnectionDatabase struct {
+	*database.MockDatabase
+	*database.MockConnectionReporter
+}
+
+func TestReadConnections(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := mockConnectionDatabase{
+		MockDatabase:           database.NewMockDatabase(ctrl),
+		MockConnectionReporter: database.NewMockConnectionReporter(ctrl),
+	}
+	tester := New(Config{}).(*TesterImpl)
+	connections := &database.Connections{Max: 100, Total: 90, Saturation: 90}
+	mockDatabase.MockConnectionReporter.EXPECT().Connections(gomock.Any()).Return(connections, nil)
+	result := Result{Database: "test"}
+	tester.readConnections(mockDatabase, context.Background(), &result)
+	assert.Equal(t, connections, result.Connections)
+}
+
+type mockSessionDatabase struct {
+	*database.MockDatabase
+	*database.MockSessionReporter
+}
+
+func TestReadSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := mockSessionDatabase{
+		MockDatabase:        database.NewMockDatabase(ctrl),
+		MockSessionReporter: database.NewMockSessionReporter(ctrl),
+	}
+	tester := New(Config{}).(*TesterImpl)
+	sessions := []database.Session{
+		{PID: 1, State: "active", LongQuery: true, LongTransaction: true},
+		{PID: 2, State: "idle in transaction", IdleInTransaction: true},
+		{PID: 3, State: "idle in transaction (aborted)", IdleInTransaction: true},
+		{PID: 4, State: "active", LongTransaction: true},
+	}
+	mockDatabase.MockSessionReporter.EXPECT().Sessions(gomock.Any()).Return(sessions, nil)
+	result := Result{Database: "test"}
+	tester.readSessions(mockDatabase, context.Background(), &result)
+	assert.Equal(t, sessions, result.Sessions)
+	assert.Equal(t, &SessionSummary{LongRunning: 1, IdleInTransaction: 2, LongTransactions: 2}, result.SessionSummary)
+}
+
+type mockWraparoundDatabase struct {
+	*database.MockDatabase
+	*database.MockWraparoundReporter
+}
+
+func TestReadWraparound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := mockWraparoundDatabase{
+		MockDatabase:           database.NewMockDatabase(ctrl),
+		MockWraparoundReporter: database.NewMockWraparoundReporter(ctrl),
+	}
+	tester := New(Config{}).(*TesterImpl)
+	wraparound := &database.Wraparound{FreezeMaxAge: 200000000, Flagged: true}
+	mockDatabase.MockWraparoundReporter.EXPECT().Wraparound(gomock.Any()).Return(wraparound, nil)
+	result := Result{Database: "test"}
+	tester.readWraparound(mockDatabase, context.Background(), &result)
+	assert.Equal(t, wraparound, result.Wraparound)
+}
+
+type mockBackupDatabase struct {
+	*database.MockDatabase
+	*database.MockBackupReporter
+}
+
+func TestReadBackup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := mockBackupDatabase{
+		MockDatabase:       database.NewMockDatabase(ctrl),
+		MockBackupReporter: database.NewMockBackupReporter(ctrl),
+	}
+	tester := New(Config{}).(*TesterImpl)
+	backup := &database.Backup{Healthy: false, Archiver: &database.Archiver{FailedCount: 1, Flagged: true}}
+	gomock.InOrder(
+		mockDatabase.MockBackupReporter.EXPECT().Backup(gomock.Any()).Return(backup, nil),
+		mockDatabase.MockBackupReporter.EXPECT().Backup(gomock.Any()).Return(nil, nil),
+	)
+	result := Result{Database: "test"}
+	tester.readBackup(mockDatabase, context.Background(), &result)
+	assert.Equal(t, false, *result.BackedUp)
+	assert.Equal(t, backup, result.Backup)
+	result = Result{Database: "test"}
+	tester.readBackup(mockDatabase, context.Background(), &result)
+	assert.Nil(t, result.BackedUp)
+	assert.Nil(t, result.Backup)
+}
+
+type mockSettingsDatabase struct {
+	*database.MockDatabase
+	*database.MockSettingsReporter
+}
+
+func TestReadSettings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := mockSettingsDatabase{
+		MockDatabase:         database.NewMockDatabase(ctrl),
+		MockSettingsReporter: database.NewMockSettingsReporter(ctrl),
+	}
+	tester := New(Config{}).(*TesterImpl)
+	settings := &database.Settings{Values: map[string]string{"max_connections": "100"}, PendingRestart: []string{"max_connections"}}
+	mockDatabase.MockSettingsReporter.EXPECT().Settings(gomock.Any()).Return(settings, nil)
+	result := Result{Database: "test"}
+	tester.readSettings(mockDatabase, context.Background(), &result)
+	assert.Equal(t, settings, result.Settings)
+}
@@ -0,0 +1,26 @@
+package tester
+
+import (
+	"context"
+	"time"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+)
+
+// schedule runs probe against every database every interval until ctx is
+// done. It is used for probes too expensive to run on every test cycle; their
+// latest outcome is attached to the regular results. Scheduled probes run
+// while the database is probed regularly, so backends must serve them on a
+// connection of their own.
+func (p *TesterImpl) schedule(ctx context.Context, interval time.Duration, probe func(db database.Database, ctx context.Context)) {
+	for {
+		for _, db := range p.config.Databases {
+			go probe(db, ctx)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
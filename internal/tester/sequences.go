@@ -0,0 +1,42 @@
+package tester
+
+import (
+	"context"
+	"time"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultSequencesInterval = 3600
+
+func (p *TesterImpl) sequencesInterval() time.Duration {
+	if p.config.SequencesInterval == 0 {
+		return defaultSequencesInterval * time.Second
+	}
+	return time.Duration(p.config.SequencesInterval) * time.Second
+}
+
+func (p *TesterImpl) collectSequences(db database.Database, ctx context.Context) {
+	reporter, ok := db.(database.SequenceReporter)
+	if !ok {
+		return
+	}
+	sequences, err := reporter.Sequences(ctx)
+	if err != nil {
+		log.Error().Msgf("checking sequences of %s: %s", db.Identifier(), err)
+		return
+	}
+	if sequences == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sequences[db.Identifier()] = sequences
+}
+
+func (p *TesterImpl) latestSequences(identifier string) *database.Sequences {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.sequences[identifier]
+}
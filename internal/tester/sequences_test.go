@@ -0,0 +1,35 @@
+package tester
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type mockSequenceDatabase struct {
+	*database.MockDatabase
+	*database.MockSequenceReporter
+}
+
+func TestCollectSequences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := mockSequenceDatabase{
+		MockDatabase:         database.NewMockDatabase(ctrl),
+		MockSequenceReporter: database.NewMockSequenceReporter(ctrl),
+	}
+	tester := New(Config{}).(*TesterImpl)
+	sequences := &database.Sequences{Timestamp: time.Now(), Threshold: 0.8}
+	mockDatabase.MockDatabase.EXPECT().Identifier().Return("test").AnyTimes()
+	mockDatabase.MockSequenceReporter.EXPECT().Sequences(gomock.Any()).Return(nil, nil)
+	mockDatabase.MockSequenceReporter.EXPECT().Sequences(gomock.Any()).Return(sequences, nil)
+	// Databases without the check enabled report nothing.
+	tester.collectSequences(mockDatabase, context.Background())
+	assert.Nil(t, tester.latestSequences("test"))
+	tester.collectSequences(mockDatabase, context.Background())
+	assert.Equal(t, sequences, tester.latestSequences("test"))
+}
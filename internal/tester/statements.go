@@ -0,0 +1,159 @@
+package tester
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultStatementsTop              = 10
+	defaultStatementsRegressionFactor = 2
+)
+
+// The baseline of a query is an exponentially weighted moving average of
+// its mean time per interval. Queries are only flagged once their baseline
+// is built from statementsBaselineSamples intervals, and only in intervals
+// with at least statementsMinCalls calls: a single slow call is noise. The
+// baseline is frozen while a query is flagged, so a lasting regression
+// doesn't become the new normal.
+const (
+	statementsBaselineWeight  = 0.2
+	statementsBaselineSamples = 3
+	statementsMinCalls        = 10
+)
+
+// StatementDelta is what a query did within the last interval.
+// BaselineMeanTime is its rolling mean time before the interval; flagged
+// queries got slower than that by more than the configured factor.
+type StatementDelta struct {
+	QueryID          int64         `json:"query_id"`
+	Query            string        `json:"query"`
+	Calls            int64         `json:"calls"`
+	TotalTime        time.Duration `json:"total_time"`
+	MeanTime         time.Duration `json:"mean_time"`
+	BaselineMeanTime time.Duration `json:"baseline_mean_time"`
+	Rows             int64         `json:"rows"`
+	SharedBlocksRead int64         `json:"shared_blocks_read"`
+	Flagged          bool          `json:"flagged"`
+}
+
+type statementBaseline struct {
+	meanTime float64
+	samples  int
+}
+
+// statementHistory is the previous snapshot and the baselines of the queries
+// of a database.
+type statementHistory struct {
+	previous  map[int64]database.Statement
+	baselines map[int64]*statementBaseline
+}
+
+func (p *TesterImpl) statementsTop() int {
+	if p.config.StatementsTop == 0 {
+		return defaultStatementsTop
+	}
+	return p.config.StatementsTop
+}
+
+func (p *TesterImpl) statementsRegressionFactor() float64 {
+	if p.config.StatementsRegressionFactor == 0 {
+		return defaultStatementsRegressionFactor
+	}
+	return p.config.StatementsRegressionFactor
+}
+
+// deltas compares statements with the previous snapshot, updates the
+// baselines and remembers statements as the new snapshot. It returns the
+// deltas of all queries called within the interval.
+func (h *statementHistory) deltas(statements []database.Statement, factor float64) []StatementDelta {
+	deltas := []StatementDelta{}
+	current := make(map[int64]database.Statement, len(statements))
+	for _, statement := range statements {
+		current[statement.QueryID] = statement
+		previous, ok := h.previous[statement.QueryID]
+		if !ok || statement.Calls <= previous.Calls || statement.TotalTime < previous.TotalTime {
+			continue
+		}
+		delta := StatementDelta{
+			QueryID:          statement.QueryID,
+			Query:            statement.Query,
+			Calls:            statement.Calls - previous.Calls,
+			TotalTime:        statement.TotalTime - previous.TotalTime,
+			Rows:             statement.Rows - previous.Rows,
+			SharedBlocksRead: statement.SharedBlocksRead - previous.SharedBlocksRead,
+		}
+		delta.MeanTime = delta.TotalTime / time.Duration(delta.Calls)
+		baseline, ok := h.baselines[statement.QueryID]
+		if !ok {
+			baseline = &statementBaseline{meanTime: float64(delta.MeanTime)}
+			h.baselines[statement.QueryID] = baseline
+		}
+		delta.BaselineMeanTime = time.Duration(baseline.meanTime)
+		delta.Flagged = baseline.samples >= statementsBaselineSamples && delta.Calls >= statementsMinCalls && float64(delta.MeanTime) > factor*baseline.meanTime
+		if !delta.Flagged {
+			baseline.meanTime = statementsBaselineWeight*float64(delta.MeanTime) + (1-statementsBaselineWeight)*baseline.meanTime
+			baseline.samples++
+		}
+		deltas = append(deltas, delta)
+	}
+	for queryID := range h.baselines {
+		if _, ok := current[queryID]; !ok {
+			delete(h.baselines, queryID)
+		}
+	}
+	h.previous = current
+	return deltas
+}
+
+// topStatements keeps the n queries that spent the most time, plus every
+// flagged one.
+func topStatements(deltas []StatementDelta, n int) []StatementDelta {
+	sort.SliceStable(deltas, func(i, j int) bool {
+		return deltas[i].TotalTime > deltas[j].TotalTime
+	})
+	top := []StatementDelta{}
+	for i, delta := range deltas {
+		if i < n || delta.Flagged {
+			top = append(top, delta)
+		}
+	}
+	return top
+}
+
+func (p *TesterImpl) readStatements(db database.Database, ctx context.Context, result *Result) {
+	reporter, ok := db.(database.StatementReporter)
+	if !ok {
+		return
+	}
+	statements, err := reporter.Statements(ctx)
+	if err != nil {
+		log.Error().Msgf("reading statement statistics of %s: %s", result.Database, err)
+		return
+	}
+	if statements == nil {
+		return
+	}
+	p.mu.Lock()
+	history, ok := p.statements[result.Database]
+	if !ok {
+		history = &statementHistory{baselines: make(map[int64]*statementBaseline)}
+		p.statements[result.Database] = history
+	}
+	first := history.previous == nil
+	deltas := history.deltas(statements, p.statementsRegressionFactor())
+	p.mu.Unlock()
+	if first {
+		return
+	}
+	result.Statements = topStatements(deltas, p.statementsTop())
+	for _, delta := range result.Statements {
+		if delta.Flagged {
+			result.StatementRegressions++
+		}
+	}
+}
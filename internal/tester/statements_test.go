@@ -0,0 +1,108 @@
+package tester
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func statement(queryID int64, calls int64, totalTime time.Duration) database.Statement {
+	return database.Statement{QueryID: queryID, Query: "SELECT 1", Calls: calls, TotalTime: totalTime}
+}
+
+func TestStatementDeltas(t *testing.T) {
+	history := &statementHistory{baselines: make(map[int64]*statementBaseline)}
+	assert.Empty(t, history.deltas([]database.Statement{statement(1, 10, 10*time.Millisecond)}, 2))
+	deltas := history.deltas([]database.Statement{
+		statement(1, 20, 20*time.Millisecond),
+		statement(2, 5, time.Second),
+	}, 2)
+	assert.Equal(t, []StatementDelta{
+		{QueryID: 1, Query: "SELECT 1", Calls: 10, TotalTime: 10 * time.Millisecond, MeanTime: time.Millisecond, BaselineMeanTime: time.Millisecond},
+	}, deltas)
+	// Build up the baseline, then slow the query down.
+	calls, total := int64(20), 20*time.Millisecond
+	for i := 0; i < statementsBaselineSamples; i++ {
+		calls, total = calls+10, total+10*time.Millisecond
+		deltas = history.deltas([]database.Statement{statement(1, calls, total)}, 2)
+		assert.False(t, deltas[0].Flagged)
+	}
+	deltas = history.deltas([]database.Statement{statement(1, calls+10, total+50*time.Millisecond)}, 2)
+	assert.Equal(t, 5*time.Millisecond, deltas[0].MeanTime)
+	assert.Equal(t, time.Millisecond, deltas[0].BaselineMeanTime)
+	assert.True(t, deltas[0].Flagged)
+	// The baseline is frozen while the regression lasts.
+	calls, total = calls+10, total+50*time.Millisecond
+	for i := 0; i < 10; i++ {
+		calls, total = calls+10, total+50*time.Millisecond
+		deltas = history.deltas([]database.Statement{statement(1, calls, total)}, 2)
+		assert.True(t, deltas[0].Flagged)
+		assert.Equal(t, time.Millisecond, deltas[0].BaselineMeanTime)
+	}
+	// Queries gone from the snapshot lose their baseline.
+	history.deltas([]database.Statement{}, 2)
+	assert.Empty(t, history.baselines)
+}
+
+func TestStatementDeltasFewCalls(t *testing.T) {
+	history := &statementHistory{baselines: make(map[int64]*statementBaseline)}
+	calls, total := int64(10), 10*time.Millisecond
+	history.deltas([]database.Statement{statement(1, calls, total)}, 2)
+	for i := 0; i <= statementsBaselineSamples; i++ {
+		calls, total = calls+10, total+10*time.Millisecond
+		history.deltas([]database.Statement{statement(1, calls, total)}, 2)
+	}
+	// One slow call within an interval is not a regression.
+	deltas := history.deltas([]database.Statement{statement(1, calls+1, total+time.Second)}, 2)
+	assert.Equal(t, time.Second, deltas[0].MeanTime)
+	assert.False(t, deltas[0].Flagged)
+}
+
+func TestTopStatements(t *testing.T) {
+	deltas := []StatementDelta{
+		{QueryID: 1, TotalTime: time.Second},
+		{QueryID: 2, TotalTime: 3 * time.Second},
+		{QueryID: 3, TotalTime: time.Millisecond, Flagged: true},
+		{QueryID: 4, TotalTime: 2 * time.Second},
+	}
+	top := topStatements(deltas, 2)
+	ids := []int64{}
+	for _, delta := range top {
+		ids = append(ids, delta.QueryID)
+	}
+	assert.Equal(t, []int64{2, 4, 3}, ids)
+}
+
+type mockStatementDatabase struct {
+	*database.MockDatabase
+	*database.MockStatementReporter
+}
+
+func TestReadStatements(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := mockStatementDatabase{
+		MockDatabase:          database.NewMockDatabase(ctrl),
+		MockStatementReporter: database.NewMockStatementReporter(ctrl),
+	}
+	tester := New(Config{}).(*TesterImpl)
+	gomock.InOrder(
+		mockDatabase.MockStatementReporter.EXPECT().Statements(gomock.Any()).Return([]database.Statement{statement(1, 10, time.Second)}, nil),
+		mockDatabase.MockStatementReporter.EXPECT().Statements(gomock.Any()).Return([]database.Statement{statement(1, 20, 2*time.Second)}, nil),
+		mockDatabase.MockStatementReporter.EXPECT().Statements(gomock.Any()).Return(nil, nil),
+	)
+	result := Result{Database: "test"}
+	tester.readStatements(mockDatabase, context.Background(), &result)
+	assert.Nil(t, result.Statements)
+	tester.readStatements(mockDatabase, context.Background(), &result)
+	assert.Len(t, result.Statements, 1)
+	assert.Equal(t, 100*time.Millisecond, result.Statements[0].MeanTime)
+	assert.Equal(t, 0, result.StatementRegressions)
+	result = Result{Database: "test"}
+	tester.readStatements(mockDatabase, context.Background(), &result)
+	assert.Nil(t, result.Statements)
+}
@@ -0,0 +1,101 @@
+package tester
+
+import (
+	"context"
+	"time"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/rs/zerolog/log"
+)
+
+// Stats are the cumulative server statistics of a probe and, from the
+// second probe on, their rates since the previous one.
+type Stats struct {
+	*database.Stats
+	Rates *StatsRates `json:"rates,omitempty"`
+}
+
+// StatsRates are per second rates of the server statistics over Interval.
+// HitRatio is the buffer cache hit ratio within the interval.
+type StatsRates struct {
+	Interval             time.Duration `json:"interval"`
+	BlocksHit            float64       `json:"blocks_hit"`
+	BlocksRead           float64       `json:"blocks_read"`
+	HitRatio             float64       `json:"hit_ratio"`
+	TempFiles            float64       `json:"temp_files"`
+	TempBytes            float64       `json:"temp_bytes"`
+	CheckpointsTimed     float64       `json:"checkpoints_timed"`
+	CheckpointsRequested float64       `json:"checkpoints_requested"`
+	BuffersCheckpoint    float64       `json:"buffers_checkpoint"`
+	BuffersClean         float64       `json:"buffers_clean"`
+	BuffersBackend       float64       `json:"buffers_backend"`
+}
+
+// statsRates derives rates from two consecutive readings. Counters going
+// backwards mean the statistics were reset or another server answered, so
+// no rates are given.
+func statsRates(previous *database.Stats, current *database.Stats) *StatsRates {
+	if previous == nil {
+		return nil
+	}
+	interval := current.Timestamp.Sub(previous.Timestamp)
+	if interval <= 0 {
+		return nil
+	}
+	deltas := []int64{
+		current.BlocksHit - previous.BlocksHit,
+		current.BlocksRead - previous.BlocksRead,
+		current.TempFiles - previous.TempFiles,
+		current.TempBytes - previous.TempBytes,
+		current.CheckpointsTimed - previous.CheckpointsTimed,
+		current.CheckpointsRequested - previous.CheckpointsRequested,
+		current.BuffersCheckpoint - previous.BuffersCheckpoint,
+		current.BuffersClean - previous.BuffersClean,
+		current.BuffersBackend - previous.BuffersBackend,
+	}
+	rates := make([]float64, len(deltas))
+	for i, delta := range deltas {
+		if delta < 0 {
+			return nil
+		}
+		rates[i] = float64(delta) / interval.Seconds()
+	}
+	statsRates := &StatsRates{
+		Interval:             interval,
+		BlocksHit:            rates[0],
+		BlocksRead:           rates[1],
+		TempFiles:            rates[2],
+		TempBytes:            rates[3],
+		CheckpointsTimed:     rates[4],
+		CheckpointsRequested: rates[5],
+		BuffersCheckpoint:    rates[6],
+		BuffersClean:         rates[7],
+		BuffersBackend:       rates[8],
+	}
+	if deltas[0]+deltas[1] > 0 {
+		statsRates.HitRatio = float64(deltas[0]) / float64(deltas[0]+deltas[1])
+	}
+	return statsRates
+}
+
+// readStats reads the server statistics of db and the rates since its
+// previous reading.
+func (p *TesterImpl) readStats(db database.Database, ctx context.Context, result *Result) {
+	reporter, ok := db.(database.StatsReporter)
+	if !ok {
+		return
+	}
+	stats, err := reporter.Stats(ctx)
+	if err != nil {
+		log.Error().Msgf("reading statistics of %s: %s", result.Database, err)
+		return
+	}
+	p.mu.Lock()
+	previous := p.stats[result.Database]
+	p.stats[result.Database] = stats
+	p.mu.Unlock()
+	result.Stats = &Stats{
+		Stats: stats,
+		Rates: statsRates(previous, stats),
+	}
+}
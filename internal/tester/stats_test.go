@@ -0,0 +1,58 @@
+package tester
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStatsRates(t *testing.T) {
+	now := time.Now()
+	previous := &database.Stats{Timestamp: now, BlocksHit: 1000, BlocksRead: 100, TempFiles: 1, CheckpointsTimed: 10, BuffersBackend: 5}
+	current := &database.Stats{Timestamp: now.Add(10 * time.Second), BlocksHit: 1900, BlocksRead: 200, TempFiles: 2, CheckpointsTimed: 11, BuffersBackend: 105}
+	assert.Equal(t, &StatsRates{
+		Interval:         10 * time.Second,
+		BlocksHit:        90,
+		BlocksRead:       10,
+		HitRatio:         0.9,
+		TempFiles:        0.1,
+		CheckpointsTimed: 0.1,
+		BuffersBackend:   10,
+	}, statsRates(previous, current))
+	assert.Nil(t, statsRates(nil, current))
+	// A statistics reset makes counters go backwards.
+	assert.Nil(t, statsRates(current, &database.Stats{Timestamp: now.Add(20 * time.Second)}))
+}
+
+type mockStatsDatabase struct {
+	*database.MockDatabase
+	*database.MockStatsReporter
+}
+
+func TestReadStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := mockStatsDatabase{
+		MockDatabase:      database.NewMockDatabase(ctrl),
+		MockStatsReporter: database.NewMockStatsReporter(ctrl),
+	}
+	tester := New(Config{}).(*TesterImpl)
+	now := time.Now()
+	first := &database.Stats{Timestamp: now, BlocksHit: 10}
+	second := &database.Stats{Timestamp: now.Add(time.Second), BlocksHit: 20}
+	gomock.InOrder(
+		mockDatabase.MockStatsReporter.EXPECT().Stats(gomock.Any()).Return(first, nil),
+		mockDatabase.MockStatsReporter.EXPECT().Stats(gomock.Any()).Return(second, nil),
+	)
+	result := Result{Database: "test"}
+	tester.readStats(mockDatabase, context.Background(), &result)
+	assert.Equal(t, &Stats{Stats: first}, result.Stats)
+	tester.readStats(mockDatabase, context.Background(), &result)
+	assert.Equal(t, second, result.Stats.Stats)
+	assert.Equal(t, 10.0, result.Stats.Rates.BlocksHit)
+	assert.Equal(t, 1.0, result.Stats.Rates.HitRatio)
+}
@@ -16,15 +16,90 @@ type Config struct {
 	Databases    []database.Database `mapstructure:"databases"`
 	TestTimeout  int                 `mapstructure:"test_timeout"`
 	TestInterval int                 `mapstructure:"test_interval"`
+	// Clusters maps database identifiers to the cluster they belong to.
+	Clusters map[string]string `mapstructure:"clusters"`
+	// VacuumInterval is how often vacuum statistics are collected, in
+	// seconds.
+	VacuumInterval int `mapstructure:"vacuum_interval"`
+	// StatementsTop is how many of the queries that spent the most time
+	// are reported per interval.
+	StatementsTop int `mapstructure:"statements_top"`
+	// StatementsRegressionFactor is how much slower than its baseline a
+	// query has to get to be flagged.
+	StatementsRegressionFactor float64 `mapstructure:"statements_regression_factor"`
+	// SequencesInterval is how often sequences are checked for exhaustion,
+	// in seconds.
+	SequencesInterval int `mapstructure:"sequences_interval"`
 }
 
 type Result struct {
 	Database       string        `json:"database"`
+	Cluster        string        `json:"cluster,omitempty"`
 	Connectable    bool          `json:"connectable"`
 	ConnectionTime time.Duration `json:"connection_time"`
 	Writable       bool          `json:"writable"`
 	WriteTime      time.Duration `json:"write_time"`
 	Readable       bool          `json:"readable"`
 	ReadTime       time.Duration `json:"read_time"`
+	BackedUp       *bool         `json:"backed_up,omitempty"`
 	Timestamp      time.Time     `json:"timestamp"`
+	database.Replication
+	Propagation []Propagation         `json:"propagation,omitempty"`
+	Connections *database.Connections `json:"connections,omitempty"`
+	// Sessions are served on their own endpoint, results only carry the
+	// SessionSummary.
+	Sessions       []database.Session   `json:"-"`
+	SessionSummary *SessionSummary      `json:"sessions,omitempty"`
+	Blocking       []database.Blocker   `json:"blocking,omitempty"`
+	Wraparound     *database.Wraparound `json:"wraparound,omitempty"`
+	Stats          *Stats               `json:"stats,omitempty"`
+	// Statements are served on their own endpoint, results only count the
+	// regressions.
+	Statements           []StatementDelta `json:"-"`
+	StatementRegressions int              `json:"statement_regressions,omitempty"`
+	Capacity             *Capacity        `json:"capacity,omitempty"`
+	// Backup explains BackedUp. Both are only set for databases with WAL
+	// archiving or a backup freshness check.
+	Backup   *database.Backup   `json:"backup,omitempty"`
+	Settings *database.Settings `json:"settings,omitempty"`
+	// Vacuum is collected on its own schedule and served on its own
+	// endpoint.
+	Vacuum *database.Vacuum `json:"-"`
+	// Sequences are checked on their own schedule and served on their own
+	// endpoint.
+	Sequences *database.Sequences `json:"-"`
+}
+
+// SessionSummary counts the sessions over the long query, idle in
+// transaction and long transaction thresholds. A session can count towards
+// more than one.
+type SessionSummary struct {
+	LongRunning       int `json:"long_running"`
+	IdleInTransaction int `json:"idle_in_transaction"`
+	LongTransactions  int `json:"long_transactions"`
+}
+
+func summarizeSessions(sessions []database.Session) *SessionSummary {
+	summary := &SessionSummary{}
+	for _, session := range sessions {
+		if session.LongQuery {
+			summary.LongRunning++
+		}
+		if session.IdleInTransaction {
+			summary.IdleInTransaction++
+		}
+		if session.LongTransaction {
+			summary.LongTransactions++
+		}
+	}
+	return summary
+}
+
+// Propagation is how long a write on a primary took to become visible on a
+// replica of its cluster.
+type Propagation struct {
+	Database string        `json:"database"`
+	Visible  bool          `json:"visible"`
+	Latency  time.Duration `json:"latency"`
+	Error    string        `json:"error,omitempty"`
 }
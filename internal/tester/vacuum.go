@@ -0,0 +1,39 @@
+package tester
+
+import (
+	"context"
+	"time"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultVacuumInterval = 300
+
+func (p *TesterImpl) vacuumInterval() time.Duration {
+	if p.config.VacuumInterval == 0 {
+		return defaultVacuumInterval * time.Second
+	}
+	return time.Duration(p.config.VacuumInterval) * time.Second
+}
+
+func (p *TesterImpl) collectVacuum(db database.Database, ctx context.Context) {
+	reporter, ok := db.(database.VacuumReporter)
+	if !ok {
+		return
+	}
+	vacuum, err := reporter.Vacuum(ctx)
+	if err != nil {
+		log.Error().Msgf("reading vacuum statistics of %s: %s", db.Identifier(), err)
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.vacuum[db.Identifier()] = vacuum
+}
+
+func (p *TesterImpl) latestVacuum(identifier string) *database.Vacuum {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.vacuum[identifier]
+}
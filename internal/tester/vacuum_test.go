@@ -0,0 +1,58 @@
+package tester
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fbufler/database-monitor/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type mockVacuumDatabase struct {
+	*database.MockDatabase
+	*database.MockVacuumReporter
+}
+
+func TestCollectVacuum(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := mockVacuumDatabase{
+		MockDatabase:       database.NewMockDatabase(ctrl),
+		MockVacuumReporter: database.NewMockVacuumReporter(ctrl),
+	}
+	tester := New(Config{}).(*TesterImpl)
+	vacuum := &database.Vacuum{Timestamp: time.Now()}
+	mockDatabase.MockDatabase.EXPECT().Identifier().Return("test").AnyTimes()
+	mockDatabase.MockVacuumReporter.EXPECT().Vacuum(gomock.Any()).Return(vacuum, nil)
+	mockDatabase.MockVacuumReporter.EXPECT().Vacuum(gomock.Any()).Return(nil, errors.New("Vacuum error"))
+	tester.collectVacuum(mockDatabase, context.Background())
+	assert.Equal(t, vacuum, tester.latestVacuum("test"))
+	// A failed collection keeps the previous statistics.
+	tester.collectVacuum(mockDatabase, context.Background())
+	assert.Equal(t, vacuum, tester.latestVacuum("test"))
+	assert.Nil(t, tester.latestVacuum("other"))
+}
+
+func TestScheduleVacuum(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := mockVacuumDatabase{
+		MockDatabase:       database.NewMockDatabase(ctrl),
+		MockVacuumReporter: database.NewMockVacuumReporter(ctrl),
+	}
+	tester := New(Config{Databases: []database.Database{mockDatabase}}).(*TesterImpl)
+	collected := make(chan struct{}, 10)
+	mockDatabase.MockDatabase.EXPECT().Identifier().Return("test").AnyTimes()
+	mockDatabase.MockVacuumReporter.EXPECT().Vacuum(gomock.Any()).DoAndReturn(func(ctx context.Context) (*database.Vacuum, error) {
+		collected <- struct{}{}
+		return &database.Vacuum{}, nil
+	}).MinTimes(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	go tester.schedule(ctx, 10*time.Millisecond, tester.collectVacuum)
+	<-collected
+	<-collected
+	cancel()
+}
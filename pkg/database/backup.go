@@ -0,0 +1,90 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const defaultMaxBackupAge = 24 * 60 * 60
+
+// Backup is the state of WAL archiving and of the configured backup
+// freshness check. It is healthy when neither reports a problem.
+type Backup struct {
+	Healthy   bool             `json:"healthy"`
+	Archiver  *Archiver        `json:"archiver,omitempty"`
+	Freshness *BackupFreshness `json:"freshness,omitempty"`
+}
+
+// Archiver is the state of continuous WAL archiving. Flagged archivers
+// failed after their last success, haven't archived for longer than
+// configured or couldn't be read.
+type Archiver struct {
+	LastArchivedWAL  string        `json:"last_archived_wal"`
+	LastArchivedTime *time.Time    `json:"last_archived_time,omitempty"`
+	LastArchivedAge  time.Duration `json:"last_archived_age"`
+	FailedCount      int64         `json:"failed_count"`
+	LastFailedWAL    string        `json:"last_failed_wal,omitempty"`
+	LastFailedTime   *time.Time    `json:"last_failed_time,omitempty"`
+	Flagged          bool          `json:"flagged"`
+	Error            string        `json:"error,omitempty"`
+}
+
+// BackupFreshness is the age of the newest backup found by Source, a query
+// or a file glob. Fresh backups are younger than MaxAge.
+type BackupFreshness struct {
+	Source string        `json:"source"`
+	Latest *time.Time    `json:"latest,omitempty"`
+	Age    time.Duration `json:"age"`
+	MaxAge time.Duration `json:"max_age"`
+	Fresh  bool          `json:"fresh"`
+	Error  string        `json:"error,omitempty"`
+}
+
+func (c *Config) maxBackupAge() time.Duration {
+	if c.MaxBackupAge == 0 {
+		return defaultMaxBackupAge * time.Second
+	}
+	return time.Duration(c.MaxBackupAge) * time.Second
+}
+
+// newestFile returns the latest modification time of the files matching
+// pattern.
+func newestFile(pattern string) (time.Time, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var newest time.Time
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+	}
+	if newest.IsZero() {
+		return time.Time{}, fmt.Errorf("no backups match %s", pattern)
+	}
+	return newest, nil
+}
+
+// freshness builds the outcome of a freshness check from the time of the
+// newest backup or the error finding it.
+func (c *Config) freshness(source string, latest time.Time, err error) *BackupFreshness {
+	freshness := &BackupFreshness{
+		Source: source,
+		MaxAge: c.maxBackupAge(),
+	}
+	if err != nil {
+		freshness.Error = err.Error()
+		return freshness
+	}
+	freshness.Latest = &latest
+	freshness.Age = time.Since(latest)
+	freshness.Fresh = freshness.Age < freshness.MaxAge
+	return freshness
+}
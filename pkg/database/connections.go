@@ -0,0 +1,23 @@
+package database
+
+// Connections is how many of the connection slots of a server are in use.
+// Saturation is the percentage of the slots available to regular roles
+// (max_connections minus superuser_reserved_connections) that are taken.
+type Connections struct {
+	Max               int              `json:"max_connections"`
+	SuperuserReserved int              `json:"superuser_reserved_connections"`
+	Total             int              `json:"total"`
+	Saturation        float64          `json:"saturation"`
+	ByState           map[string]int   `json:"by_state"`
+	ByApplication     map[string]int   `json:"by_application"`
+	ByUser            map[string]int   `json:"by_user"`
+	Roles             []RoleConnection `json:"roles,omitempty"`
+}
+
+// RoleConnection is the usage of a role with a connection limit.
+type RoleConnection struct {
+	Role        string `json:"role"`
+	Limit       int    `json:"limit"`
+	Connections int    `json:"connections"`
+	Headroom    int    `json:"headroom"`
+}
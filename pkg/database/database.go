@@ -3,17 +3,35 @@ package database
 import "context"
 
 type Config struct {
-	FilePath          string `mapstructure:"file_path"`
-	Host              string `mapstructure:"host"`
-	Port              int    `mapstructure:"port"`
-	Username          string `mapstructure:"username"`
-	Password          string `mapstructure:"password"`
-	Database          string `mapstructure:"database"`
-	UseSSL            bool   `mapstructure:"use_ssl"`
-	SSLCertPath       string `mapstructure:"ssl_cert_path"`
-	SSLKeyPath        string `mapstructure:"ssl_key_path"`
-	SSLRootCertPath   string `mapstructure:"ssl_root_cert_path"`
-	ConnectionTimeout int    `mapstructure:"connection_timeout"`
+	Type                       string  `mapstructure:"type"`
+	Cluster                    string  `mapstructure:"cluster"`
+	FilePath                   string  `mapstructure:"file_path"`
+	Host                       string  `mapstructure:"host"`
+	Port                       int     `mapstructure:"port"`
+	Username                   string  `mapstructure:"username"`
+	Password                   string  `mapstructure:"password"`
+	Database                   string  `mapstructure:"database"`
+	UseSSL                     bool    `mapstructure:"use_ssl"`
+	SSLCertPath                string  `mapstructure:"ssl_cert_path"`
+	SSLKeyPath                 string  `mapstructure:"ssl_key_path"`
+	SSLRootCertPath            string  `mapstructure:"ssl_root_cert_path"`
+	ConnectionTimeout          int     `mapstructure:"connection_timeout"`
+	MaxSlotRetainedBytes       int64   `mapstructure:"max_slot_retained_bytes"`
+	ExpectedSyncStandbys       int     `mapstructure:"expected_sync_standbys"`
+	PatroniURL                 string  `mapstructure:"patroni_url"`
+	LongQueryThreshold         int     `mapstructure:"long_query_threshold"`
+	IdleInTransactionThreshold int     `mapstructure:"idle_in_transaction_threshold"`
+	LongTransactionThreshold   int     `mapstructure:"long_transaction_threshold"`
+	MaxWraparoundPercent       float64 `mapstructure:"max_wraparound_percent"`
+	BloatTables                int     `mapstructure:"bloat_tables"`
+	SizeBudgetBytes            int64   `mapstructure:"size_budget_bytes"`
+	MaxArchiveAge              int     `mapstructure:"max_archive_age"`
+	BackupQuery                string  `mapstructure:"backup_query"`
+	BackupPath                 string  `mapstructure:"backup_path"`
+	MaxBackupAge               int     `mapstructure:"max_backup_age"`
+	CheckSequences             bool    `mapstructure:"check_sequences"`
+	SequenceThreshold          float64 `mapstructure:"sequence_threshold"`
+	DesiredSettings            string  `mapstructure:"desired_settings"`
 }
 
 type Database interface {
@@ -25,3 +43,60 @@ type Database interface {
 	TestRead(ctx context.Context) error
 	SetupTestTable(ctx context.Context) error
 }
+
+type ReplicationReporter interface {
+	Replication(ctx context.Context) (*Replication, error)
+}
+
+type PropagationProber interface {
+	WriteToken(ctx context.Context, token string) error
+	WaitForToken(ctx context.Context, token string) error
+	DeleteToken(ctx context.Context, token string) error
+}
+
+type ConnectionReporter interface {
+	Connections(ctx context.Context) (*Connections, error)
+}
+
+type SessionReporter interface {
+	Sessions(ctx context.Context) ([]Session, error)
+}
+
+type LockReporter interface {
+	Locks(ctx context.Context) ([]Blocker, error)
+}
+
+type WraparoundReporter interface {
+	Wraparound(ctx context.Context) (*Wraparound, error)
+}
+
+type VacuumReporter interface {
+	Vacuum(ctx context.Context) (*Vacuum, error)
+}
+
+type StatsReporter interface {
+	Stats(ctx context.Context) (*Stats, error)
+}
+
+// StatementReporter returns nil when per-query statistics aren't available.
+type StatementReporter interface {
+	Statements(ctx context.Context) ([]Statement, error)
+}
+
+type SizeReporter interface {
+	Sizes(ctx context.Context) (*Sizes, error)
+}
+
+// BackupReporter returns nil when there is nothing to check.
+type BackupReporter interface {
+	Backup(ctx context.Context) (*Backup, error)
+}
+
+// SequenceReporter returns nil when the check isn't enabled.
+type SequenceReporter interface {
+	Sequences(ctx context.Context) (*Sequences, error)
+}
+
+type SettingsReporter interface {
+	Settings(ctx context.Context) (*Settings, error)
+}
@@ -135,3 +135,524 @@ func (mr *MockDatabaseMockRecorder) TestWrite(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestWrite", reflect.TypeOf((*MockDatabase)(nil).TestWrite), ctx)
 }
+
+// MockReplicationReporter is a mock of ReplicationReporter interface.
+type MockReplicationReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockReplicationReporterMockRecorder
+}
+
+// MockReplicationReporterMockRecorder is the mock recorder for MockReplicationReporter.
+type MockReplicationReporterMockRecorder struct {
+	mock *MockReplicationReporter
+}
+
+// NewMockReplicationReporter creates a new mock instance.
+func NewMockReplicationReporter(ctrl *gomock.Controller) *MockReplicationReporter {
+	mock := &MockReplicationReporter{ctrl: ctrl}
+	mock.recorder = &MockReplicationReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReplicationReporter) EXPECT() *MockReplicationReporterMockRecorder {
+	return m.recorder
+}
+
+// Replication mocks base method.
+func (m *MockReplicationReporter) Replication(ctx context.Context) (*Replication, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replication", ctx)
+	ret0, _ := ret[0].(*Replication)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replication indicates an expected call of Replication.
+func (mr *MockReplicationReporterMockRecorder) Replication(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replication", reflect.TypeOf((*MockReplicationReporter)(nil).Replication), ctx)
+}
+
+// MockPropagationProber is a mock of PropagationProber interface.
+type MockPropagationProber struct {
+	ctrl     *gomock.Controller
+	recorder *MockPropagationProberMockRecorder
+}
+
+// MockPropagationProberMockRecorder is the mock recorder for MockPropagationProber.
+type MockPropagationProberMockRecorder struct {
+	mock *MockPropagationProber
+}
+
+// NewMockPropagationProber creates a new mock instance.
+func NewMockPropagationProber(ctrl *gomock.Controller) *MockPropagationProber {
+	mock := &MockPropagationProber{ctrl: ctrl}
+	mock.recorder = &MockPropagationProberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPropagationProber) EXPECT() *MockPropagationProberMockRecorder {
+	return m.recorder
+}
+
+// DeleteToken mocks base method.
+func (m *MockPropagationProber) DeleteToken(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteToken indicates an expected call of DeleteToken.
+func (mr *MockPropagationProberMockRecorder) DeleteToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteToken", reflect.TypeOf((*MockPropagationProber)(nil).DeleteToken), ctx, token)
+}
+
+// WaitForToken mocks base method.
+func (m *MockPropagationProber) WaitForToken(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitForToken indicates an expected call of WaitForToken.
+func (mr *MockPropagationProberMockRecorder) WaitForToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForToken", reflect.TypeOf((*MockPropagationProber)(nil).WaitForToken), ctx, token)
+}
+
+// WriteToken mocks base method.
+func (m *MockPropagationProber) WriteToken(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteToken indicates an expected call of WriteToken.
+func (mr *MockPropagationProberMockRecorder) WriteToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteToken", reflect.TypeOf((*MockPropagationProber)(nil).WriteToken), ctx, token)
+}
+
+// MockConnectionReporter is a mock of ConnectionReporter interface.
+type MockConnectionReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockConnectionReporterMockRecorder
+}
+
+// MockConnectionReporterMockRecorder is the mock recorder for MockConnectionReporter.
+type MockConnectionReporterMockRecorder struct {
+	mock *MockConnectionReporter
+}
+
+// NewMockConnectionReporter creates a new mock instance.
+func NewMockConnectionReporter(ctrl *gomock.Controller) *MockConnectionReporter {
+	mock := &MockConnectionReporter{ctrl: ctrl}
+	mock.recorder = &MockConnectionReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConnectionReporter) EXPECT() *MockConnectionReporterMockRecorder {
+	return m.recorder
+}
+
+// Connections mocks base method.
+func (m *MockConnectionReporter) Connections(ctx context.Context) (*Connections, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Connections", ctx)
+	ret0, _ := ret[0].(*Connections)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Connections indicates an expected call of Connections.
+func (mr *MockConnectionReporterMockRecorder) Connections(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connections", reflect.TypeOf((*MockConnectionReporter)(nil).Connections), ctx)
+}
+
+// MockSessionReporter is a mock of SessionReporter interface.
+type MockSessionReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionReporterMockRecorder
+}
+
+// MockSessionReporterMockRecorder is the mock recorder for MockSessionReporter.
+type MockSessionReporterMockRecorder struct {
+	mock *MockSessionReporter
+}
+
+// NewMockSessionReporter creates a new mock instance.
+func NewMockSessionReporter(ctrl *gomock.Controller) *MockSessionReporter {
+	mock := &MockSessionReporter{ctrl: ctrl}
+	mock.recorder = &MockSessionReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionReporter) EXPECT() *MockSessionReporterMockRecorder {
+	return m.recorder
+}
+
+// Sessions mocks base method.
+func (m *MockSessionReporter) Sessions(ctx context.Context) ([]Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sessions", ctx)
+	ret0, _ := ret[0].([]Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sessions indicates an expected call of Sessions.
+func (mr *MockSessionReporterMockRecorder) Sessions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sessions", reflect.TypeOf((*MockSessionReporter)(nil).Sessions), ctx)
+}
+
+// MockLockReporter is a mock of LockReporter interface.
+type MockLockReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockReporterMockRecorder
+}
+
+// MockLockReporterMockRecorder is the mock recorder for MockLockReporter.
+type MockLockReporterMockRecorder struct {
+	mock *MockLockReporter
+}
+
+// NewMockLockReporter creates a new mock instance.
+func NewMockLockReporter(ctrl *gomock.Controller) *MockLockReporter {
+	mock := &MockLockReporter{ctrl: ctrl}
+	mock.recorder = &MockLockReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLockReporter) EXPECT() *MockLockReporterMockRecorder {
+	return m.recorder
+}
+
+// Locks mocks base method.
+func (m *MockLockReporter) Locks(ctx context.Context) ([]Blocker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Locks", ctx)
+	ret0, _ := ret[0].([]Blocker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Locks indicates an expected call of Locks.
+func (mr *MockLockReporterMockRecorder) Locks(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Locks", reflect.TypeOf((*MockLockReporter)(nil).Locks), ctx)
+}
+
+// MockWraparoundReporter is a mock of WraparoundReporter interface.
+type MockWraparoundReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockWraparoundReporterMockRecorder
+}
+
+// MockWraparoundReporterMockRecorder is the mock recorder for MockWraparoundReporter.
+type MockWraparoundReporterMockRecorder struct {
+	mock *MockWraparoundReporter
+}
+
+// NewMockWraparoundReporter creates a new mock instance.
+func NewMockWraparoundReporter(ctrl *gomock.Controller) *MockWraparoundReporter {
+	mock := &MockWraparoundReporter{ctrl: ctrl}
+	mock.recorder = &MockWraparoundReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWraparoundReporter) EXPECT() *MockWraparoundReporterMockRecorder {
+	return m.recorder
+}
+
+// Wraparound mocks base method.
+func (m *MockWraparoundReporter) Wraparound(ctx context.Context) (*Wraparound, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Wraparound", ctx)
+	ret0, _ := ret[0].(*Wraparound)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Wraparound indicates an expected call of Wraparound.
+func (mr *MockWraparoundReporterMockRecorder) Wraparound(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Wraparound", reflect.TypeOf((*MockWraparoundReporter)(nil).Wraparound), ctx)
+}
+
+// MockVacuumReporter is a mock of VacuumReporter interface.
+type MockVacuumReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockVacuumReporterMockRecorder
+}
+
+// MockVacuumReporterMockRecorder is the mock recorder for MockVacuumReporter.
+type MockVacuumReporterMockRecorder struct {
+	mock *MockVacuumReporter
+}
+
+// NewMockVacuumReporter creates a new mock instance.
+func NewMockVacuumReporter(ctrl *gomock.Controller) *MockVacuumReporter {
+	mock := &MockVacuumReporter{ctrl: ctrl}
+	mock.recorder = &MockVacuumReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVacuumReporter) EXPECT() *MockVacuumReporterMockRecorder {
+	return m.recorder
+}
+
+// Vacuum mocks base method.
+func (m *MockVacuumReporter) Vacuum(ctx context.Context) (*Vacuum, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Vacuum", ctx)
+	ret0, _ := ret[0].(*Vacuum)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Vacuum indicates an expected call of Vacuum.
+func (mr *MockVacuumReporterMockRecorder) Vacuum(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vacuum", reflect.TypeOf((*MockVacuumReporter)(nil).Vacuum), ctx)
+}
+
+// MockStatsReporter is a mock of StatsReporter interface.
+type MockStatsReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsReporterMockRecorder
+}
+
+// MockStatsReporterMockRecorder is the mock recorder for MockStatsReporter.
+type MockStatsReporterMockRecorder struct {
+	mock *MockStatsReporter
+}
+
+// NewMockStatsReporter creates a new mock instance.
+func NewMockStatsReporter(ctrl *gomock.Controller) *MockStatsReporter {
+	mock := &MockStatsReporter{ctrl: ctrl}
+	mock.recorder = &MockStatsReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatsReporter) EXPECT() *MockStatsReporterMockRecorder {
+	return m.recorder
+}
+
+// Stats mocks base method.
+func (m *MockStatsReporter) Stats(ctx context.Context) (*Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx)
+	ret0, _ := ret[0].(*Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockStatsReporterMockRecorder) Stats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockStatsReporter)(nil).Stats), ctx)
+}
+
+// MockStatementReporter is a mock of StatementReporter interface.
+type MockStatementReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatementReporterMockRecorder
+}
+
+// MockStatementReporterMockRecorder is the mock recorder for MockStatementReporter.
+type MockStatementReporterMockRecorder struct {
+	mock *MockStatementReporter
+}
+
+// NewMockStatementReporter creates a new mock instance.
+func NewMockStatementReporter(ctrl *gomock.Controller) *MockStatementReporter {
+	mock := &MockStatementReporter{ctrl: ctrl}
+	mock.recorder = &MockStatementReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatementReporter) EXPECT() *MockStatementReporterMockRecorder {
+	return m.recorder
+}
+
+// Statements mocks base method.
+func (m *MockStatementReporter) Statements(ctx context.Context) ([]Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Statements", ctx)
+	ret0, _ := ret[0].([]Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Statements indicates an expected call of Statements.
+func (mr *MockStatementReporterMockRecorder) Statements(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Statements", reflect.TypeOf((*MockStatementReporter)(nil).Statements), ctx)
+}
+
+// MockSizeReporter is a mock of SizeReporter interface.
+type MockSizeReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockSizeReporterMockRecorder
+}
+
+// MockSizeReporterMockRecorder is the mock recorder for MockSizeReporter.
+type MockSizeReporterMockRecorder struct {
+	mock *MockSizeReporter
+}
+
+// NewMockSizeReporter creates a new mock instance.
+func NewMockSizeReporter(ctrl *gomock.Controller) *MockSizeReporter {
+	mock := &MockSizeReporter{ctrl: ctrl}
+	mock.recorder = &MockSizeReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSizeReporter) EXPECT() *MockSizeReporterMockRecorder {
+	return m.recorder
+}
+
+// Sizes mocks base method.
+func (m *MockSizeReporter) Sizes(ctx context.Context) (*Sizes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sizes", ctx)
+	ret0, _ := ret[0].(*Sizes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sizes indicates an expected call of Sizes.
+func (mr *MockSizeReporterMockRecorder) Sizes(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sizes", reflect.TypeOf((*MockSizeReporter)(nil).Sizes), ctx)
+}
+
+// MockBackupReporter is a mock of BackupReporter interface.
+type MockBackupReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackupReporterMockRecorder
+}
+
+// MockBackupReporterMockRecorder is the mock recorder for MockBackupReporter.
+type MockBackupReporterMockRecorder struct {
+	mock *MockBackupReporter
+}
+
+// NewMockBackupReporter creates a new mock instance.
+func NewMockBackupReporter(ctrl *gomock.Controller) *MockBackupReporter {
+	mock := &MockBackupReporter{ctrl: ctrl}
+	mock.recorder = &MockBackupReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackupReporter) EXPECT() *MockBackupReporterMockRecorder {
+	return m.recorder
+}
+
+// Backup mocks base method.
+func (m *MockBackupReporter) Backup(ctx context.Context) (*Backup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backup", ctx)
+	ret0, _ := ret[0].(*Backup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Backup indicates an expected call of Backup.
+func (mr *MockBackupReporterMockRecorder) Backup(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backup", reflect.TypeOf((*MockBackupReporter)(nil).Backup), ctx)
+}
+
+// MockSequenceReporter is a mock of SequenceReporter interface.
+type MockSequenceReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockSequenceReporterMockRecorder
+}
+
+// MockSequenceReporterMockRecorder is the mock recorder for MockSequenceReporter.
+type MockSequenceReporterMockRecorder struct {
+	mock *MockSequenceReporter
+}
+
+// NewMockSequenceReporter creates a new mock instance.
+func NewMockSequenceReporter(ctrl *gomock.Controller) *MockSequenceReporter {
+	mock := &MockSequenceReporter{ctrl: ctrl}
+	mock.recorder = &MockSequenceReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSequenceReporter) EXPECT() *MockSequenceReporterMockRecorder {
+	return m.recorder
+}
+
+// Sequences mocks base method.
+func (m *MockSequenceReporter) Sequences(ctx context.Context) (*Sequences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sequences", ctx)
+	ret0, _ := ret[0].(*Sequences)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sequences indicates an expected call of Sequences.
+func (mr *MockSequenceReporterMockRecorder) Sequences(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sequences", reflect.TypeOf((*MockSequenceReporter)(nil).Sequences), ctx)
+}
+
+// MockSettingsReporter is a mock of SettingsReporter interface.
+type MockSettingsReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockSettingsReporterMockRecorder
+}
+
+// MockSettingsReporterMockRecorder is the mock recorder for MockSettingsReporter.
+type MockSettingsReporterMockRecorder struct {
+	mock *MockSettingsReporter
+}
+
+// NewMockSettingsReporter creates a new mock instance.
+func NewMockSettingsReporter(ctrl *gomock.Controller) *MockSettingsReporter {
+	mock := &MockSettingsReporter{ctrl: ctrl}
+	mock.recorder = &MockSettingsReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSettingsReporter) EXPECT() *MockSettingsReporterMockRecorder {
+	return m.recorder
+}
+
+// Settings mocks base method.
+func (m *MockSettingsReporter) Settings(ctx context.Context) (*Settings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Settings", ctx)
+	ret0, _ := ret[0].(*Settings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Settings indicates an expected call of Settings.
+func (mr *MockSettingsReporterMockRecorder) Settings(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Settings", reflect.TypeOf((*MockSettingsReporter)(nil).Settings), ctx)
+}
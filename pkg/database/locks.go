@@ -0,0 +1,34 @@
+package database
+
+import "time"
+
+// Lock is a lock a session waits for or holds on a contested relation.
+type Lock struct {
+	Type     string `json:"type"`
+	Mode     string `json:"mode"`
+	Relation string `json:"relation,omitempty"`
+	Granted  bool   `json:"granted"`
+}
+
+// Blocker is a session in a blocking tree. Roots are sessions that block
+// others without waiting themselves. Duration is how long a waiting session
+// has been waiting and, for roots, how long their transaction has been open.
+type Blocker struct {
+	PID         int           `json:"pid"`
+	User        string        `json:"user"`
+	Application string        `json:"application"`
+	State       string        `json:"state"`
+	Duration    time.Duration `json:"duration"`
+	Query       string        `json:"query"`
+	Locks       []Lock        `json:"locks"`
+	Waiters     []Blocker     `json:"waiters,omitempty"`
+}
+
+// Waiting counts the sessions waiting behind b, directly or transitively.
+func (b Blocker) Waiting() int {
+	waiting := 0
+	for _, waiter := range b.Waiters {
+		waiting += 1 + waiter.Waiting()
+	}
+	return waiting
+}
@@ -0,0 +1,234 @@
+package database
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/rs/zerolog/log"
+)
+
+func init() {
+	Register("mysql", NewMySQL)
+}
+
+type MySQL struct {
+	Config     Config
+	identifier string
+	db         *sql.DB
+	// tlsConfigName is what the TLS settings are registered as with the
+	// driver, tlsErr why they couldn't be.
+	tlsConfigName string
+	tlsErr        error
+}
+
+func (c *Config) mysqlTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName: c.Host,
+	}
+	if c.SSLRootCertPath != "" {
+		log.Debug().Msgf("Using SSL root cert: %s", c.SSLRootCertPath)
+		rootCert, err := os.ReadFile(c.SSLRootCertPath)
+		if err != nil {
+			return nil, err
+		}
+		rootCertPool := x509.NewCertPool()
+		if !rootCertPool.AppendCertsFromPEM(rootCert) {
+			return nil, fmt.Errorf("parsing SSL root cert %s", c.SSLRootCertPath)
+		}
+		tlsConfig.RootCAs = rootCertPool
+	}
+	if c.SSLCertPath != "" || c.SSLKeyPath != "" {
+		log.Debug().Msgf("Using SSL cert: %s", c.SSLCertPath)
+		log.Debug().Msgf("Using SSL key: %s", c.SSLKeyPath)
+		certificate, err := tls.LoadX509KeyPair(c.SSLCertPath, c.SSLKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
+
+func (c *Config) mysqlConnectionString(tlsConfigName string) string {
+	log.Debug().Msg("Creating mysql connection string")
+	mysqlConfig := mysql.NewConfig()
+	mysqlConfig.User = c.Username
+	mysqlConfig.Passwd = c.Password
+	mysqlConfig.Net = "tcp"
+	mysqlConfig.Addr = fmt.Sprintf("%s:%d", c.Host, c.Port)
+	mysqlConfig.DBName = c.Database
+	if tlsConfigName != "" {
+		log.Debug().Msg("Using SSL")
+		mysqlConfig.TLSConfig = tlsConfigName
+	} else {
+		log.Debug().Msg("Not using SSL")
+	}
+	timeout := c.ConnectionTimeout
+	if timeout == 0 {
+		log.Debug().Msgf("No connection timeout provided, using default connection timeout of %d seconds", defaultConnectionTimeout)
+		timeout = defaultConnectionTimeout
+	}
+	mysqlConfig.Timeout = time.Duration(timeout) * time.Second
+	return mysqlConfig.FormatDSN()
+}
+
+// NewMySQL defaults the config and registers its TLS settings up front, the
+// driver keeps them in a global registry. They are registered per database,
+// so databases on the same address can use different certificates.
+func NewMySQL(cfg Config) Database {
+	if cfg.ConnectionTimeout == 0 {
+		cfg.ConnectionTimeout = defaultConnectionTimeout
+	}
+	m := &MySQL{
+		Config:     cfg,
+		identifier: fmt.Sprintf("%s:%d/%s", cfg.Host, cfg.Port, cfg.Database),
+	}
+	if cfg.UseSSL {
+		tlsConfig, err := cfg.mysqlTLSConfig()
+		if err == nil {
+			m.tlsConfigName = fmt.Sprintf("dbm-%s", m.identifier)
+			err = mysql.RegisterTLSConfig(m.tlsConfigName, tlsConfig)
+		}
+		m.tlsErr = err
+	}
+	return m
+}
+
+func (m *MySQL) Connect() error {
+	log.Debug().Msgf("%s: Connecting to mysql", m.identifier)
+	if m.tlsErr != nil {
+		return m.tlsErr
+	}
+	db, err := sql.Open("mysql", m.Config.mysqlConnectionString(m.tlsConfigName))
+	if err != nil {
+		return err
+	}
+	log.Debug().Msgf("%s: Connected to mysql", m.identifier)
+	m.db = db
+	return nil
+}
+
+func (m *MySQL) Close() error {
+	log.Debug().Msgf("%s: Closing mysql connection", m.identifier)
+	if m.db == nil {
+		return nil
+	}
+	err := m.db.Close()
+	m.db = nil
+	return err
+}
+
+func (m *MySQL) Identifier() string {
+	return m.identifier
+}
+
+func (m *MySQL) Test(ctx context.Context) error {
+	log.Debug().Msgf("%s: Testing mysql", m.identifier)
+	if m.db == nil {
+		log.Debug().Msgf("%s: No connection, connecting", m.identifier)
+		err := m.Connect()
+		if err != nil {
+			return err
+		}
+		defer m.Close()
+	}
+	errChan := make(chan error)
+	go func() {
+		errChan <- m.TestWrite(ctx)
+	}()
+	go func() {
+		errChan <- m.TestRead(ctx)
+	}()
+	returnCount := 0
+	var testError error
+	log.Debug().Msgf("%s: Waiting for test completion", m.identifier)
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s: context terminated", m.identifier)
+		case err := <-errChan:
+			if err != nil {
+				log.Debug().Msgf("%s: Received error: %s", m.identifier, err)
+				if testError != nil {
+					testError = fmt.Errorf("%s\n%s", testError, err)
+				} else {
+					testError = err
+				}
+			}
+			returnCount++
+		}
+		if returnCount == 2 {
+			log.Debug().Msgf("%s: All tests completed", m.identifier)
+			return testError
+		}
+	}
+}
+
+func (m *MySQL) TestWrite(ctx context.Context) error {
+	log.Debug().Msgf("%s: Testing mysql write", m.identifier)
+	if m.db == nil {
+		log.Debug().Msgf("%s: No connection, connecting", m.identifier)
+		err := m.Connect()
+		if err != nil {
+			return err
+		}
+		defer m.Close()
+	}
+	log.Debug().Msgf("%s: Writing test data", m.identifier)
+	_, err := m.db.ExecContext(ctx, "INSERT INTO test (test) VALUES ('test')")
+	if err != nil {
+		return err
+	}
+	log.Debug().Msgf("%s: Test data written", m.identifier)
+	return nil
+}
+
+func (m *MySQL) TestRead(ctx context.Context) error {
+	log.Debug().Msgf("%s: Testing mysql read", m.identifier)
+	if m.db == nil {
+		log.Debug().Msgf("%s: No connection, connecting", m.identifier)
+		err := m.Connect()
+		if err != nil {
+			return err
+		}
+		defer m.Close()
+	}
+	log.Debug().Msgf("%s: Reading test data", m.identifier)
+	rows, err := m.db.QueryContext(ctx, "SELECT * FROM test")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	log.Debug().Msgf("%s: Test data read", m.identifier)
+	return nil
+}
+
+func (m *MySQL) SetupTestTable(ctx context.Context) error {
+	log.Debug().Msgf("%s: Setting up test table", m.identifier)
+	if m.db == nil {
+		log.Debug().Msgf("%s: No connection, connecting", m.identifier)
+		err := m.Connect()
+		if err != nil {
+			return err
+		}
+		defer m.Close()
+	}
+	log.Debug().Msgf("%s: Dropping test table", m.identifier)
+	_, err := m.db.ExecContext(ctx, "DROP TABLE IF EXISTS test")
+	if err != nil {
+		return err
+	}
+	log.Debug().Msgf("%s: Creating test table", m.identifier)
+	_, err = m.db.ExecContext(ctx, "CREATE TABLE test (test varchar(255))")
+	if err != nil {
+		return err
+	}
+	log.Debug().Msgf("%s: Test table created", m.identifier)
+	return nil
+}
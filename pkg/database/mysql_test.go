@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func mysqlSessionBuilder(pro *memory.DbProvider) server.SessionBuilder {
+	return func(ctx context.Context, conn *mysql.Conn, addr string) (sql.Session, error) {
+		client := sql.Client{Address: addr, Capabilities: conn.Capabilities}
+		return memory.NewSession(sql.NewBaseSessionWithClientServer(addr, client, conn.ConnectionID), pro), nil
+	}
+}
+
+func startMySQLServer(t *testing.T) Config {
+	db := memory.NewDatabase("testdb")
+	pro := memory.NewDBProvider(db)
+	engine := sqle.NewDefault(pro)
+	s, err := server.NewServer(server.Config{
+		Protocol: "tcp",
+		Address:  "localhost:0",
+	}, engine, mysqlSessionBuilder(pro), nil)
+	if err != nil {
+		t.Fatalf("starting mysql server: %s", err)
+	}
+	go s.Start()
+	t.Cleanup(func() {
+		s.Close()
+	})
+	addr := s.Listener.Addr().(*net.TCPAddr)
+	return Config{
+		Host:     "localhost",
+		Port:     addr.Port,
+		Username: "root",
+		Database: "testdb",
+	}
+}
+
+func TestMySQLConnectionString(t *testing.T) {
+	cfg := Config{
+		Host:              "localhost",
+		Port:              3306,
+		Username:          "testuser",
+		Password:          "testpassword",
+		Database:          "testdb",
+		UseSSL:            false,
+		ConnectionTimeout: 5,
+	}
+	expected := "testuser:testpassword@tcp(localhost:3306)/testdb?timeout=5s"
+	actual := cfg.mysqlConnectionString("")
+	assert.Equal(t, expected, actual)
+}
+
+func TestMySQLConnectionStringSSL(t *testing.T) {
+	cfg := Config{
+		Host:              "localhost",
+		Port:              3306,
+		Username:          "testuser",
+		Password:          "testpassword",
+		Database:          "testdb",
+		UseSSL:            true,
+		ConnectionTimeout: 0,
+	}
+	db := NewMySQL(cfg).(*MySQL)
+	assert.NoError(t, db.tlsErr)
+	expected := "testuser:testpassword@tcp(localhost:3306)/testdb?timeout=5s&tls=dbm-localhost%3A3306%2Ftestdb"
+	actual := db.Config.mysqlConnectionString(db.tlsConfigName)
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, "testuser:testpassword@tcp(localhost:3306)/testdb?timeout=5s", cfg.mysqlConnectionString(""))
+	assert.Equal(t, 0, cfg.ConnectionTimeout)
+}
+
+func TestMySQLTLSConfigPerDatabase(t *testing.T) {
+	first := NewMySQL(Config{Host: "localhost", Port: 3306, Database: "first", UseSSL: true}).(*MySQL)
+	second := NewMySQL(Config{Host: "localhost", Port: 3306, Database: "second", UseSSL: true}).(*MySQL)
+	assert.NotEqual(t, first.tlsConfigName, second.tlsConfigName)
+}
+
+func TestMySQLConnectionStringSSLMissingCert(t *testing.T) {
+	db := NewMySQL(Config{
+		Host:            "localhost",
+		Port:            3306,
+		UseSSL:          true,
+		SSLRootCertPath: "/path/to/rootcert",
+	})
+	assert.Error(t, db.Connect())
+}
+
+func TestMySQL(t *testing.T) {
+	cfg := startMySQLServer(t)
+	ctx := context.Background()
+	db := NewMySQL(cfg)
+	assert.Equal(t, fmt.Sprintf("localhost:%d/testdb", cfg.Port), db.Identifier())
+	assert.NoError(t, db.SetupTestTable(ctx))
+	assert.NoError(t, db.Test(ctx))
+	assert.NoError(t, db.Connect())
+	defer db.Close()
+	assert.NoError(t, db.TestWrite(ctx))
+	assert.NoError(t, db.TestRead(ctx))
+}
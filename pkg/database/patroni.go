@@ -0,0 +1,156 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// patroniLagToleranceBytes is how far Patroni's and dbm's lag may differ
+// before they are considered to disagree. Both are sampled at slightly
+// different times, so one WAL segment of difference is expected.
+const patroniLagToleranceBytes = 16 << 20
+
+// Patroni is Patroni's view of a node compared with what dbm measured over
+// SQL.
+type Patroni struct {
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	State    string `json:"state"`
+	Timeline int64  `json:"timeline"`
+	Leader   string `json:"leader"`
+	// Lag is how many bytes the node is behind the leader according to
+	// Patroni, nil when Patroni reports it as unknown.
+	Lag           *int64   `json:"lag,omitempty"`
+	Disagreements []string `json:"disagreements"`
+	Error         string   `json:"error,omitempty"`
+}
+
+type patroniStatus struct {
+	State    string `json:"state"`
+	Role     string `json:"role"`
+	Timeline int64  `json:"timeline"`
+	Patroni  struct {
+		Name string `json:"name"`
+	} `json:"patroni"`
+}
+
+type patroniCluster struct {
+	Members []struct {
+		Name string          `json:"name"`
+		Role string          `json:"role"`
+		Host string          `json:"host"`
+		Port int             `json:"port"`
+		Lag  json.RawMessage `json:"lag"`
+	} `json:"members"`
+}
+
+func getPatroni(ctx context.Context, client *http.Client, url string, target any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	// /patroni answers 503 while postgres is down but still has a body.
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusServiceUnavailable {
+		return fmt.Errorf("GET %s: %s", url, response.Status)
+	}
+	return json.NewDecoder(response.Body).Decode(target)
+}
+
+// patroniLag parses the lag of a member, a number of bytes behind the leader
+// or "unknown". ok is false when Patroni couldn't tell.
+func patroniLag(raw json.RawMessage) (lag int64, ok bool) {
+	if err := json.Unmarshal(raw, &lag); err != nil {
+		return 0, false
+	}
+	return lag, true
+}
+
+// patroniIsPrimary reports whether a Patroni role means the node accepts
+// writes. A standby leader leads a standby cluster and is in recovery.
+func patroniIsPrimary(role string) bool {
+	return role == "master" || role == "primary" || role == "leader"
+}
+
+// patroni queries the Patroni REST API of the node and compares its view
+// with the replication state measured over SQL. Failing to reach Patroni is
+// reported in the result rather than failing the probe.
+func (p *Postgres) patroni(ctx context.Context, replication *Replication) *Patroni {
+	log.Debug().Msgf("%s: Cross-checking with Patroni at %s", p.identifier, p.Config.PatroniURL)
+	timeout := p.Config.ConnectionTimeout
+	if timeout == 0 {
+		timeout = 5
+	}
+	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
+	baseURL := strings.TrimSuffix(p.Config.PatroniURL, "/")
+	result := &Patroni{Disagreements: []string{}}
+	status := patroniStatus{}
+	err := getPatroni(ctx, client, baseURL+"/patroni", &status)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Name = status.Patroni.Name
+	result.Role = status.Role
+	result.State = status.State
+	result.Timeline = status.Timeline
+	cluster := patroniCluster{}
+	err = getPatroni(ctx, client, baseURL+"/cluster", &cluster)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	// Patroni measures lag against the leader's position, which only the
+	// primary can compare with: pg_stat_replication has the same distance
+	// for every standby.
+	standbyLag := make(map[string]int64)
+	for _, standby := range replication.Standbys {
+		standbyLag[standby.Name] = standby.ReplayLagBytes
+	}
+	lagDisagreements := []string{}
+	for _, member := range cluster.Members {
+		if patroniIsPrimary(member.Role) || member.Role == "standby_leader" {
+			result.Leader = member.Name
+		}
+		lag, known := patroniLag(member.Lag)
+		if !known {
+			continue
+		}
+		self := member.Name == result.Name || (result.Name == "" && member.Host == p.Config.Host && member.Port == p.Config.Port)
+		if self {
+			result.Lag = &lag
+		}
+		measured, ok := standbyLag[member.Name]
+		if replication.Role != RolePrimary || !ok {
+			continue
+		}
+		if difference := lag - measured; difference > patroniLagToleranceBytes || difference < -patroniLagToleranceBytes {
+			lagDisagreements = append(lagDisagreements, fmt.Sprintf("patroni reports %d bytes of lag for %s but it is %d bytes behind", lag, member.Name, measured))
+		}
+	}
+
+	if patroniIsPrimary(result.Role) && replication.Role == RoleStandby {
+		result.Disagreements = append(result.Disagreements, fmt.Sprintf("patroni reports %s but node is in recovery", result.Role))
+	}
+	if !patroniIsPrimary(result.Role) && replication.Role == RolePrimary {
+		result.Disagreements = append(result.Disagreements, fmt.Sprintf("patroni reports %s but node is not in recovery", result.Role))
+	}
+	if replication.Role == RolePrimary && result.Leader != "" && result.Leader != result.Name {
+		result.Disagreements = append(result.Disagreements, fmt.Sprintf("patroni leader is %s but node is primary", result.Leader))
+	}
+	if result.Timeline != 0 && replication.Timeline != 0 && result.Timeline != replication.Timeline {
+		result.Disagreements = append(result.Disagreements, fmt.Sprintf("patroni reports timeline %d but node is on timeline %d", result.Timeline, replication.Timeline))
+	}
+	result.Disagreements = append(result.Disagreements, lagDisagreements...)
+	return result
+}
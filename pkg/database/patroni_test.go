@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func startPatroni(t *testing.T, status string, cluster string) string {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/patroni", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(status))
+	})
+	mux.HandleFunc("/cluster", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(cluster))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+const patroniTestCluster = `{"members": [
+	{"name": "pg1", "role": "leader", "state": "running", "host": "10.0.0.1", "port": 5432, "timeline": 4},
+	{"name": "pg2", "role": "replica", "state": "streaming", "host": "10.0.0.2", "port": 5432, "timeline": 4, "lag": 2048},
+	{"name": "pg3", "role": "replica", "state": "stopped", "host": "10.0.0.3", "port": 5432, "lag": "unknown"}
+]}`
+
+func TestPatroniAgrees(t *testing.T) {
+	url := startPatroni(t, `{"state": "running", "role": "replica", "timeline": 4, "patroni": {"name": "pg2"}}`, patroniTestCluster)
+	p := NewPostgres(Config{Host: "10.0.0.2", Port: 5432, PatroniURL: url + "/"}).(*Postgres)
+	// A standby's own replay lag is no measure of the distance to the
+	// leader, so it isn't compared.
+	result := p.patroni(context.Background(), &Replication{Role: RoleStandby, Timeline: 4, ReplayLagBytes: 1 << 30})
+	lag := int64(2048)
+	assert.Equal(t, &Patroni{
+		Name:          "pg2",
+		Role:          "replica",
+		State:         "running",
+		Timeline:      4,
+		Leader:        "pg1",
+		Lag:           &lag,
+		Disagreements: []string{},
+	}, result)
+}
+
+func TestPatroniLeaderInRecovery(t *testing.T) {
+	url := startPatroni(t, `{"state": "running", "role": "primary", "timeline": 4, "patroni": {"name": "pg1"}}`, patroniTestCluster)
+	p := NewPostgres(Config{Host: "10.0.0.1", Port: 5432, PatroniURL: url}).(*Postgres)
+	result := p.patroni(context.Background(), &Replication{Role: RoleStandby, Timeline: 3})
+	assert.Equal(t, []string{
+		"patroni reports primary but node is in recovery",
+		"patroni reports timeline 4 but node is on timeline 3",
+	}, result.Disagreements)
+}
+
+func TestPatroniReplicaNotInRecovery(t *testing.T) {
+	url := startPatroni(t, `{"state": "running", "role": "replica", "timeline": 4}`, patroniTestCluster)
+	p := NewPostgres(Config{Host: "10.0.0.3", Port: 5432, PatroniURL: url}).(*Postgres)
+	result := p.patroni(context.Background(), &Replication{Role: RolePrimary, Timeline: 4})
+	// pg3 reports its lag as unknown.
+	assert.Nil(t, result.Lag)
+	assert.Equal(t, []string{
+		"patroni reports replica but node is not in recovery",
+		"patroni leader is pg1 but node is primary",
+	}, result.Disagreements)
+}
+
+func TestPatroniLag(t *testing.T) {
+	url := startPatroni(t, `{"state": "running", "role": "primary", "timeline": 4, "patroni": {"name": "pg1"}}`, patroniTestCluster)
+	p := NewPostgres(Config{PatroniURL: url}).(*Postgres)
+	result := p.patroni(context.Background(), &Replication{Role: RolePrimary, Timeline: 4, Standbys: []Standby{
+		{Name: "pg2", ReplayLagBytes: 1 << 30},
+		{Name: "pg3", ReplayLagBytes: 1 << 30},
+	}})
+	assert.Nil(t, result.Lag)
+	assert.Equal(t, []string{"patroni reports 2048 bytes of lag for pg2 but it is 1073741824 bytes behind"}, result.Disagreements)
+}
+
+func TestPatroniLagAgrees(t *testing.T) {
+	url := startPatroni(t, `{"state": "running", "role": "primary", "timeline": 4, "patroni": {"name": "pg1"}}`, patroniTestCluster)
+	p := NewPostgres(Config{PatroniURL: url}).(*Postgres)
+	result := p.patroni(context.Background(), &Replication{Role: RolePrimary, Timeline: 4, Standbys: []Standby{
+		{Name: "pg2", ReplayLagBytes: 4096},
+	}})
+	assert.Equal(t, []string{}, result.Disagreements)
+}
+
+func TestPatroniUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	p := NewPostgres(Config{PatroniURL: server.URL}).(*Postgres)
+	result := p.patroni(context.Background(), &Replication{Role: RolePrimary})
+	assert.NotEmpty(t, result.Error)
+}
+
+func TestPatroniNotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+	p := NewPostgres(Config{PatroniURL: server.URL}).(*Postgres)
+	result := p.patroni(context.Background(), &Replication{Role: RolePrimary})
+	assert.Contains(t, result.Error, "404")
+}
@@ -9,6 +9,14 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+func init() {
+	Register("postgres", NewPostgres)
+}
+
+// defaultConnectionTimeout is used when connection_timeout is not set, in
+// seconds.
+const defaultConnectionTimeout = 5
+
 type Postgres struct {
 	Config     Config
 	identifier string
@@ -37,15 +45,21 @@ func (c *Config) postgresConnectionString() string {
 		log.Debug().Msg("Not using SSL")
 		connectionString = fmt.Sprintf("%s sslmode=disable", connectionString)
 	}
-	if c.ConnectionTimeout == 0 {
-		log.Debug().Msg("No connection timeout provided, using default connection timeout of 5 seconds")
-		c.ConnectionTimeout = 5
+	timeout := c.ConnectionTimeout
+	if timeout == 0 {
+		log.Debug().Msgf("No connection timeout provided, using default connection timeout of %d seconds", defaultConnectionTimeout)
+		timeout = defaultConnectionTimeout
 	}
-	connectionString = fmt.Sprintf("%s connect_timeout=%d", connectionString, c.ConnectionTimeout)
+	connectionString = fmt.Sprintf("%s connect_timeout=%d", connectionString, timeout)
 	return connectionString
 }
 
+// NewPostgres defaults the config up front: the probes, propagation and the
+// vacuum and sequences schedules read it from different goroutines.
 func NewPostgres(cfg Config) Database {
+	if cfg.ConnectionTimeout == 0 {
+		cfg.ConnectionTimeout = defaultConnectionTimeout
+	}
 	return &Postgres{
 		Config:     cfg,
 		identifier: fmt.Sprintf("%s:%d/%s", cfg.Host, cfg.Port, cfg.Database),
@@ -63,6 +77,14 @@ func (p *Postgres) Connect() error {
 	return nil
 }
 
+// serverVersion returns the server version as a number, e.g. 160002 for
+// 16.2.
+func (p *Postgres) serverVersion(ctx context.Context) (int, error) {
+	var version int
+	err := p.db.QueryRowContext(ctx, "SELECT current_setting('server_version_num')::int").Scan(&version)
+	return version, err
+}
+
 func (p *Postgres) Close() error {
 	log.Debug().Msgf("%s: Closing postgres connection", p.identifier)
 	return p.db.Close()
@@ -124,8 +146,12 @@ func (p *Postgres) TestWrite(ctx context.Context) error {
 		}
 		defer p.Close()
 	}
+	return p.write(ctx, "test")
+}
+
+func (p *Postgres) write(ctx context.Context, value string) error {
 	log.Debug().Msgf("%s: Writing test data", p.identifier)
-	_, err := p.db.ExecContext(ctx, "INSERT INTO test (test) VALUES ('test')")
+	_, err := p.db.ExecContext(ctx, "INSERT INTO test (test) VALUES ($1)", value)
 	if err != nil {
 		return err
 	}
@@ -173,6 +199,12 @@ func (p *Postgres) SetupTestTable(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	// Replicas look up propagation tokens on every poll; without an index
+	// that is a scan of the whole, ever growing table.
+	_, err = p.db.ExecContext(ctx, "CREATE INDEX test_test_idx ON test (test)")
+	if err != nil {
+		return err
+	}
 	log.Debug().Msgf("%s: Test table created", p.identifier)
 	return nil
 }
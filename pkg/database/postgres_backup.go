@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const postgresArchiverQuery = `SELECT
+	current_setting('archive_mode'),
+	pg_is_in_recovery(),
+	COALESCE(last_archived_wal, ''),
+	last_archived_time,
+	failed_count,
+	COALESCE(last_failed_wal, ''),
+	last_failed_time
+FROM pg_stat_archiver`
+
+// Backup returns nil when neither archiving nor a freshness check is
+// configured.
+func (p *Postgres) Backup(ctx context.Context) (*Backup, error) {
+	log.Debug().Msgf("%s: Checking backups", p.identifier)
+	if p.db == nil {
+		log.Debug().Msgf("%s: No connection, connecting", p.identifier)
+		err := p.Connect()
+		if err != nil {
+			return nil, err
+		}
+		defer p.Close()
+	}
+	backup := &Backup{Healthy: true}
+	var err error
+	backup.Archiver, err = p.archiver(ctx)
+	if err != nil {
+		// Reported next to the freshness check rather than instead of it.
+		backup.Archiver = &Archiver{Error: err.Error(), Flagged: true}
+	}
+	if backup.Archiver != nil && backup.Archiver.Flagged {
+		backup.Healthy = false
+	}
+	switch {
+	case p.Config.BackupQuery != "":
+		var latest time.Time
+		err = p.db.QueryRowContext(ctx, p.Config.BackupQuery).Scan(&latest)
+		backup.Freshness = p.Config.freshness(p.Config.BackupQuery, latest, err)
+	case p.Config.BackupPath != "":
+		latest, err := newestFile(p.Config.BackupPath)
+		backup.Freshness = p.Config.freshness(p.Config.BackupPath, latest, err)
+	}
+	if backup.Freshness != nil && !backup.Freshness.Fresh {
+		backup.Healthy = false
+	}
+	if backup.Archiver == nil && backup.Freshness == nil {
+		return nil, nil
+	}
+	return backup, nil
+}
+
+func (p *Postgres) archiver(ctx context.Context) (*Archiver, error) {
+	archiver := &Archiver{}
+	var mode string
+	var inRecovery bool
+	var lastArchivedTime, lastFailedTime sql.NullTime
+	err := p.db.QueryRowContext(ctx, postgresArchiverQuery).Scan(&mode, &inRecovery, &archiver.LastArchivedWAL, &lastArchivedTime, &archiver.FailedCount, &archiver.LastFailedWAL, &lastFailedTime)
+	if err != nil {
+		return nil, err
+	}
+	if mode == "off" {
+		log.Debug().Msgf("%s: WAL archiving is off", p.identifier)
+		return nil, nil
+	}
+	// Standbys only archive with archive_mode set to always.
+	if inRecovery && mode != "always" {
+		log.Debug().Msgf("%s: WAL archiving is left to the primary", p.identifier)
+		return nil, nil
+	}
+	archiver.LastArchivedTime = nullTime(lastArchivedTime)
+	archiver.LastFailedTime = nullTime(lastFailedTime)
+	if archiver.LastArchivedTime != nil {
+		archiver.LastArchivedAge = time.Since(*archiver.LastArchivedTime)
+	}
+	if archiver.LastFailedTime != nil && (archiver.LastArchivedTime == nil || archiver.LastFailedTime.After(*archiver.LastArchivedTime)) {
+		archiver.Flagged = true
+	}
+	// Archiving that never succeeded has no last archived time to age.
+	if p.Config.MaxArchiveAge > 0 && (archiver.LastArchivedTime == nil || archiver.LastArchivedAge > time.Duration(p.Config.MaxArchiveAge)*time.Second) {
+		archiver.Flagged = true
+	}
+	return archiver, nil
+}
@@ -0,0 +1,158 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var postgresArchiverColumns = []string{"archive_mode", "in_recovery", "last_archived_wal", "last_archived_time", "failed_count", "last_failed_wal", "last_failed_time"}
+
+func TestPostgresBackupArchiver(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	archived := time.Now().Add(-time.Minute)
+	failed := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta(postgresArchiverQuery)).
+		WillReturnRows(sqlmock.NewRows(postgresArchiverColumns).
+			AddRow("on", false, "000000010000000000000003", archived, 2, "000000010000000000000004", failed))
+	backup, err := p.Backup(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, backup.Healthy)
+	assert.Nil(t, backup.Freshness)
+	assert.Equal(t, "000000010000000000000003", backup.Archiver.LastArchivedWAL)
+	assert.Equal(t, &archived, backup.Archiver.LastArchivedTime)
+	assert.InDelta(t, time.Minute, backup.Archiver.LastArchivedAge, float64(time.Second))
+	assert.Equal(t, int64(2), backup.Archiver.FailedCount)
+	assert.Equal(t, &failed, backup.Archiver.LastFailedTime)
+	assert.True(t, backup.Archiver.Flagged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBackupArchiverAge(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	p.Config.MaxArchiveAge = 600
+	mock.ExpectQuery(regexp.QuoteMeta(postgresArchiverQuery)).
+		WillReturnRows(sqlmock.NewRows(postgresArchiverColumns).
+			AddRow("on", false, "000000010000000000000003", time.Now().Add(-time.Minute), 0, "", nil))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresArchiverQuery)).
+		WillReturnRows(sqlmock.NewRows(postgresArchiverColumns).
+			AddRow("on", false, "000000010000000000000003", time.Now().Add(-time.Hour), 0, "", nil))
+	backup, err := p.Backup(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, backup.Healthy)
+	backup, err = p.Backup(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, backup.Healthy)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBackupNeverArchived(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	p.Config.MaxArchiveAge = 600
+	mock.ExpectQuery(regexp.QuoteMeta(postgresArchiverQuery)).
+		WillReturnRows(sqlmock.NewRows(postgresArchiverColumns).AddRow("on", false, "", nil, 0, "", nil))
+	backup, err := p.Backup(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, backup.Healthy)
+	assert.True(t, backup.Archiver.Flagged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBackupArchiverError(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	p.Config.BackupQuery = "SELECT max(finished) FROM backups"
+	mock.ExpectQuery(regexp.QuoteMeta(postgresArchiverQuery)).
+		WillReturnError(errors.New("permission denied for view pg_stat_archiver"))
+	mock.ExpectQuery(regexp.QuoteMeta(p.Config.BackupQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(time.Now().Add(-time.Hour)))
+	backup, err := p.Backup(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, backup.Healthy)
+	assert.Equal(t, "permission denied for view pg_stat_archiver", backup.Archiver.Error)
+	assert.True(t, backup.Archiver.Flagged)
+	assert.True(t, backup.Freshness.Fresh)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBackupStandby(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	p.Config.MaxArchiveAge = 600
+	mock.ExpectQuery(regexp.QuoteMeta(postgresArchiverQuery)).
+		WillReturnRows(sqlmock.NewRows(postgresArchiverColumns).AddRow("on", true, "", nil, 0, "", nil))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresArchiverQuery)).
+		WillReturnRows(sqlmock.NewRows(postgresArchiverColumns).AddRow("always", true, "", nil, 0, "", nil))
+	backup, err := p.Backup(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, backup)
+	backup, err = p.Backup(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, backup.Healthy)
+	assert.True(t, backup.Archiver.Flagged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBackupNothingConfigured(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	mock.ExpectQuery(regexp.QuoteMeta(postgresArchiverQuery)).
+		WillReturnRows(sqlmock.NewRows(postgresArchiverColumns).AddRow("off", false, "", nil, 0, "", nil))
+	backup, err := p.Backup(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, backup)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBackupQuery(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	p.Config.BackupQuery = "SELECT max(finished) FROM backups"
+	p.Config.MaxBackupAge = 3600
+	mock.ExpectQuery(regexp.QuoteMeta(postgresArchiverQuery)).
+		WillReturnRows(sqlmock.NewRows(postgresArchiverColumns).AddRow("off", false, "", nil, 0, "", nil))
+	mock.ExpectQuery(regexp.QuoteMeta(p.Config.BackupQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(time.Now().Add(-2 * time.Hour)))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresArchiverQuery)).
+		WillReturnRows(sqlmock.NewRows(postgresArchiverColumns).AddRow("off", false, "", nil, 0, "", nil))
+	mock.ExpectQuery(regexp.QuoteMeta(p.Config.BackupQuery)).
+		WillReturnError(errors.New(`relation "backups" does not exist`))
+	backup, err := p.Backup(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, backup.Healthy)
+	assert.Nil(t, backup.Archiver)
+	assert.Equal(t, time.Hour, backup.Freshness.MaxAge)
+	assert.False(t, backup.Freshness.Fresh)
+	backup, err = p.Backup(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, backup.Healthy)
+	assert.Equal(t, `relation "backups" does not exist`, backup.Freshness.Error)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBackupPath(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	dir := t.TempDir()
+	for _, name := range []string{"base-1.tar.gz", "base-2.tar.gz"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600))
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	assert.NoError(t, os.Chtimes(filepath.Join(dir, "base-1.tar.gz"), old, old))
+	p.Config.BackupPath = filepath.Join(dir, "base-*.tar.gz")
+	mock.ExpectQuery(regexp.QuoteMeta(postgresArchiverQuery)).
+		WillReturnRows(sqlmock.NewRows(postgresArchiverColumns).AddRow("off", false, "", nil, 0, "", nil))
+	backup, err := p.Backup(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, backup.Healthy)
+	assert.True(t, backup.Freshness.Fresh)
+	assert.Less(t, backup.Freshness.Age, time.Minute)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNewestFileNoMatch(t *testing.T) {
+	_, err := newestFile(filepath.Join(t.TempDir(), "*.tar.gz"))
+	assert.ErrorContains(t, err, "no backups match")
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+const postgresConnectionLimitsQuery = `SELECT
+	current_setting('max_connections')::int,
+	current_setting('superuser_reserved_connections')::int`
+
+// Only client backends count against max_connections.
+const postgresBackendsQuery = `SELECT
+	COALESCE(state, ''),
+	COALESCE(application_name, ''),
+	COALESCE(usename, ''),
+	count(*)
+FROM pg_stat_activity
+WHERE backend_type = 'client backend'
+GROUP BY 1, 2, 3`
+
+// Roles without a limit have rolconnlimit -1.
+const postgresRoleConnectionsQuery = `SELECT
+	r.rolname,
+	r.rolconnlimit,
+	count(a.pid)
+FROM pg_roles r
+LEFT JOIN pg_stat_activity a ON a.usename = r.rolname AND a.backend_type = 'client backend'
+WHERE r.rolcanlogin AND r.rolconnlimit >= 0
+GROUP BY 1, 2
+ORDER BY 1`
+
+func (p *Postgres) Connections(ctx context.Context) (*Connections, error) {
+	log.Debug().Msgf("%s: Reading connections", p.identifier)
+	if p.db == nil {
+		log.Debug().Msgf("%s: No connection, connecting", p.identifier)
+		err := p.Connect()
+		if err != nil {
+			return nil, err
+		}
+		defer p.Close()
+	}
+	connections := &Connections{
+		ByState:       make(map[string]int),
+		ByApplication: make(map[string]int),
+		ByUser:        make(map[string]int),
+		Roles:         []RoleConnection{},
+	}
+	err := p.db.QueryRowContext(ctx, postgresConnectionLimitsQuery).Scan(&connections.Max, &connections.SuperuserReserved)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := p.db.QueryContext(ctx, postgresBackendsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var state, application, user string
+		var count int
+		err = rows.Scan(&state, &application, &user, &count)
+		if err != nil {
+			return nil, err
+		}
+		connections.Total += count
+		connections.ByState[state] += count
+		connections.ByApplication[application] += count
+		connections.ByUser[user] += count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if available := connections.Max - connections.SuperuserReserved; available > 0 {
+		connections.Saturation = float64(connections.Total) / float64(available) * 100
+	}
+	roles, err := p.db.QueryContext(ctx, postgresRoleConnectionsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer roles.Close()
+	for roles.Next() {
+		role := RoleConnection{}
+		err = roles.Scan(&role.Role, &role.Limit, &role.Connections)
+		if err != nil {
+			return nil, err
+		}
+		role.Headroom = role.Limit - role.Connections
+		connections.Roles = append(connections.Roles, role)
+	}
+	return connections, roles.Err()
+}
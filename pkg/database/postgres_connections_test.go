@@ -0,0 +1,42 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresConnections(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	mock.ExpectQuery(regexp.QuoteMeta(postgresConnectionLimitsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"max_connections", "superuser_reserved_connections"}).AddRow(103, 3))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresBackendsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"state", "application_name", "usename", "count"}).
+			AddRow("active", "api", "app", 20).
+			AddRow("idle", "api", "app", 50).
+			AddRow("idle in transaction", "worker", "app", 5).
+			AddRow("idle", "psql", "admin", 5))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresRoleConnectionsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"rolname", "rolconnlimit", "count"}).
+			AddRow("app", 80, 75).
+			AddRow("reporting", 10, 0))
+	connections, err := p.Connections(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &Connections{
+		Max:               103,
+		SuperuserReserved: 3,
+		Total:             80,
+		Saturation:        80,
+		ByState:           map[string]int{"active": 20, "idle": 55, "idle in transaction": 5},
+		ByApplication:     map[string]int{"api": 70, "worker": 5, "psql": 5},
+		ByUser:            map[string]int{"app": 75, "admin": 5},
+		Roles: []RoleConnection{
+			{Role: "app", Limit: 80, Connections: 75, Headroom: 5},
+			{Role: "reporting", Limit: 10, Connections: 0, Headroom: 10},
+		},
+	}, connections)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,148 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// postgresLocksQuery returns a row per lock of every session that waits or
+// blocks: the lock a waiting session asks for and the locks sessions hold on
+// relations someone waits for.
+const postgresLocksQuery = `WITH waiting AS (
+	SELECT pid, pg_blocking_pids(pid) AS blocked_by
+	FROM pg_stat_activity
+	WHERE cardinality(pg_blocking_pids(pid)) > 0
+), involved AS (
+	SELECT pid FROM waiting
+	UNION
+	SELECT unnest(blocked_by) FROM waiting
+)
+SELECT
+	a.pid,
+	COALESCE(array_to_string(w.blocked_by, ','), ''),
+	COALESCE(a.usename, ''),
+	COALESCE(a.application_name, ''),
+	COALESCE(a.state, ''),
+	COALESCE(extract(epoch FROM now() - CASE WHEN w.pid IS NOT NULL THEN %s ELSE a.xact_start END), 0),
+	left(a.query, $1),
+	COALESCE(l.locktype, ''),
+	COALESCE(l.mode, ''),
+	COALESCE(l.relation::regclass::text, ''),
+	COALESCE(l.granted, true)
+FROM involved i
+JOIN pg_stat_activity a ON a.pid = i.pid
+LEFT JOIN waiting w ON w.pid = a.pid
+LEFT JOIN pg_locks l ON l.pid = a.pid
+	AND (NOT l.granted OR l.relation IN (SELECT relation FROM pg_locks WHERE NOT granted))
+ORDER BY a.pid, 10, 9`
+
+// pg_locks.waitstart is available from PostgreSQL 14 on, before that the
+// start of the waiting query is used.
+const postgresLocksWaitStartVersion = 140000
+
+func (p *Postgres) Locks(ctx context.Context) ([]Blocker, error) {
+	log.Debug().Msgf("%s: Reading blocking locks", p.identifier)
+	if p.db == nil {
+		log.Debug().Msgf("%s: No connection, connecting", p.identifier)
+		err := p.Connect()
+		if err != nil {
+			return nil, err
+		}
+		defer p.Close()
+	}
+	version, err := p.serverVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	waitStart := "a.query_start"
+	if version >= postgresLocksWaitStartVersion {
+		waitStart = "COALESCE(l.waitstart, a.query_start)"
+	}
+	rows, err := p.db.QueryContext(ctx, fmt.Sprintf(postgresLocksQuery, waitStart), postgresSessionQueryLength)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	sessions := make(map[int]*Blocker)
+	blockedBy := make(map[int][]int)
+	order := []int{}
+	for rows.Next() {
+		session := Blocker{}
+		lock := Lock{}
+		var blockers string
+		var duration float64
+		err = rows.Scan(&session.PID, &blockers, &session.User, &session.Application, &session.State, &duration, &session.Query, &lock.Type, &lock.Mode, &lock.Relation, &lock.Granted)
+		if err != nil {
+			return nil, err
+		}
+		existing, ok := sessions[session.PID]
+		if !ok {
+			session.Locks = []Lock{}
+			existing = &session
+			sessions[session.PID] = existing
+			order = append(order, session.PID)
+			blockedBy[session.PID], err = parsePIDs(blockers)
+			if err != nil {
+				return nil, err
+			}
+		}
+		// Sessions have a row per lock, each with its own start; take the
+		// longest so the duration doesn't depend on the row order.
+		if d := secondsToDuration(duration); d > existing.Duration {
+			existing.Duration = d
+		}
+		if lock.Type != "" {
+			existing.Locks = append(existing.Locks, lock)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	waiters := make(map[int][]int)
+	for _, pid := range order {
+		for _, blocker := range blockedBy[pid] {
+			waiters[blocker] = append(waiters[blocker], pid)
+		}
+	}
+	trees := []Blocker{}
+	for _, pid := range order {
+		if len(blockedBy[pid]) == 0 {
+			trees = append(trees, blockingTree(pid, sessions, waiters, map[int]bool{}))
+		}
+	}
+	return trees, nil
+}
+
+// blockingTree builds the tree of sessions waiting behind pid. Sessions
+// blocked by several others show up under each of them.
+func blockingTree(pid int, sessions map[int]*Blocker, waiters map[int][]int, visited map[int]bool) Blocker {
+	node := *sessions[pid]
+	visited[pid] = true
+	defer delete(visited, pid)
+	for _, waiter := range waiters[pid] {
+		if visited[waiter] || sessions[waiter] == nil {
+			continue
+		}
+		node.Waiters = append(node.Waiters, blockingTree(waiter, sessions, waiters, visited))
+	}
+	return node
+}
+
+func parsePIDs(value string) ([]int, error) {
+	pids := []int{}
+	if value == "" {
+		return pids, nil
+	}
+	for _, field := range strings.Split(value, ",") {
+		pid, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("parsing blocking pids %q: %w", value, err)
+		}
+		pids = append(pids, pid)
+	}
+	return pids, nil
+}
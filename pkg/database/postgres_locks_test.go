@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var postgresLocksColumns = []string{"pid", "blocked_by", "usename", "application_name", "state", "duration", "query", "locktype", "mode", "relation", "granted"}
+
+func TestPostgresLocks(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	expectServerVersion(mock, 160002)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(postgresLocksQuery, "COALESCE(l.waitstart, a.query_start)"))).
+		WithArgs(postgresSessionQueryLength).
+		WillReturnRows(sqlmock.NewRows(postgresLocksColumns).
+			AddRow(10, "", "admin", "migrate", "idle in transaction", 120, "ALTER TABLE orders ADD COLUMN note text", "relation", "AccessExclusiveLock", "orders", true).
+			AddRow(11, "10", "app", "api", "active", 30, "SELECT * FROM orders", "relation", "AccessShareLock", "orders", false).
+			AddRow(12, "10,11", "app", "api", "active", 5, "INSERT INTO orders VALUES (1)", "relation", "RowExclusiveLock", "orders", false))
+	trees, err := p.Locks(context.Background())
+	assert.NoError(t, err)
+	waiter := Blocker{
+		PID:         12,
+		User:        "app",
+		Application: "api",
+		State:       "active",
+		Duration:    5 * time.Second,
+		Query:       "INSERT INTO orders VALUES (1)",
+		Locks:       []Lock{{Type: "relation", Mode: "RowExclusiveLock", Relation: "orders"}},
+	}
+	assert.Equal(t, []Blocker{
+		{
+			PID:         10,
+			User:        "admin",
+			Application: "migrate",
+			State:       "idle in transaction",
+			Duration:    2 * time.Minute,
+			Query:       "ALTER TABLE orders ADD COLUMN note text",
+			Locks:       []Lock{{Type: "relation", Mode: "AccessExclusiveLock", Relation: "orders", Granted: true}},
+			Waiters: []Blocker{
+				{
+					PID:         11,
+					User:        "app",
+					Application: "api",
+					State:       "active",
+					Duration:    30 * time.Second,
+					Query:       "SELECT * FROM orders",
+					Locks:       []Lock{{Type: "relation", Mode: "AccessShareLock", Relation: "orders"}},
+					Waiters:     []Blocker{waiter},
+				},
+				waiter,
+			},
+		},
+	}, trees)
+	assert.Equal(t, 3, trees[0].Waiting())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresLocksBeforeWaitStart(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	expectServerVersion(mock, 130000)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(postgresLocksQuery, "a.query_start"))).
+		WithArgs(postgresSessionQueryLength).
+		WillReturnRows(sqlmock.NewRows(postgresLocksColumns))
+	trees, err := p.Locks(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Blocker{}, trees)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresLocksLongestDuration(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	expectServerVersion(mock, 160002)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(postgresLocksQuery, "COALESCE(l.waitstart, a.query_start)"))).
+		WithArgs(postgresSessionQueryLength).
+		WillReturnRows(sqlmock.NewRows(postgresLocksColumns).
+			AddRow(10, "", "admin", "migrate", "idle in transaction", 120, "LOCK orders", "relation", "AccessExclusiveLock", "orders", true).
+			AddRow(11, "10", "app", "api", "active", 5, "SELECT * FROM orders JOIN items USING (id)", "relation", "AccessShareLock", "items", true).
+			AddRow(11, "10", "app", "api", "active", 30, "SELECT * FROM orders JOIN items USING (id)", "relation", "AccessShareLock", "orders", false))
+	trees, err := p.Locks(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, trees[0].Waiters[0].Duration)
+	assert.Len(t, trees[0].Waiters[0].Locks, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
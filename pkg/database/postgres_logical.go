@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Only the apply worker (relid IS NULL) tells whether a subscription is
+// running; table sync workers come and go.
+const postgresSubscriptionsQuery = `SELECT
+	s.subname,
+	s.subenabled,
+	st.pid IS NOT NULL,
+	st.last_msg_send_time,
+	st.last_msg_receipt_time,
+	st.latest_end_time
+FROM pg_subscription s
+LEFT JOIN pg_stat_subscription st ON st.subid = s.oid AND st.relid IS NULL
+WHERE s.subdbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+ORDER BY s.subname`
+
+const postgresSubscriptionTablesQuery = `SELECT
+	s.subname,
+	r.srrelid::regclass::text,
+	r.srsubstate
+FROM pg_subscription_rel r
+JOIN pg_subscription s ON s.oid = r.srsubid
+WHERE r.srsubstate <> 'r'
+	AND s.subdbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+ORDER BY s.subname, 2`
+
+// postgresSubscriptionTableStates names the srsubstate codes.
+var postgresSubscriptionTableStates = map[string]string{
+	"i": "initialize",
+	"d": "data copy",
+	"f": "finished copy",
+	"s": "synchronized",
+	"r": "ready",
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func (p *Postgres) subscriptions(ctx context.Context) ([]Subscription, error) {
+	log.Debug().Msgf("%s: Reading subscriptions", p.identifier)
+	rows, err := p.db.QueryContext(ctx, postgresSubscriptionsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	subscriptions := []Subscription{}
+	index := make(map[string]int)
+	for rows.Next() {
+		subscription := Subscription{TablesNotReady: []SubscriptionTable{}}
+		var lastMessageSent, lastMessageReceived, latestEndTime sql.NullTime
+		err = rows.Scan(&subscription.Name, &subscription.Enabled, &subscription.WorkerRunning, &lastMessageSent, &lastMessageReceived, &latestEndTime)
+		if err != nil {
+			return nil, err
+		}
+		subscription.LastMessageSent = nullTime(lastMessageSent)
+		subscription.LastMessageReceived = nullTime(lastMessageReceived)
+		subscription.LatestEndTime = nullTime(latestEndTime)
+		subscription.Flagged = subscription.Enabled && !subscription.WorkerRunning
+		index[subscription.Name] = len(subscriptions)
+		subscriptions = append(subscriptions, subscription)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(subscriptions) == 0 {
+		return subscriptions, nil
+	}
+	tables, err := p.db.QueryContext(ctx, postgresSubscriptionTablesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer tables.Close()
+	for tables.Next() {
+		var name, state string
+		table := SubscriptionTable{}
+		err = tables.Scan(&name, &table.Table, &state)
+		if err != nil {
+			return nil, err
+		}
+		table.State = postgresSubscriptionTableStates[state]
+		if table.State == "" {
+			table.State = state
+		}
+		if i, ok := index[name]; ok {
+			subscriptions[i].TablesNotReady = append(subscriptions[i].TablesNotReady, table)
+		}
+	}
+	return subscriptions, tables.Err()
+}
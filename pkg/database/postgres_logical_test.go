@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresSubscriptions(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta(postgresSubscriptionsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"subname", "subenabled", "worker_running", "last_msg_send_time", "last_msg_receipt_time", "latest_end_time"}).
+			AddRow("reporting", true, true, now, now, now).
+			AddRow("stalled", true, false, nil, nil, nil).
+			AddRow("paused", false, false, nil, nil, nil))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresSubscriptionTablesQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"subname", "table", "state"}).
+			AddRow("reporting", "public.orders", "d").
+			AddRow("reporting", "public.users", "s"))
+	subscriptions, err := p.subscriptions(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Subscription{
+		{
+			Name:                "reporting",
+			Enabled:             true,
+			WorkerRunning:       true,
+			LastMessageSent:     &now,
+			LastMessageReceived: &now,
+			LatestEndTime:       &now,
+			TablesNotReady: []SubscriptionTable{
+				{Table: "public.orders", State: "data copy"},
+				{Table: "public.users", State: "synchronized"},
+			},
+		},
+		{Name: "stalled", Enabled: true, TablesNotReady: []SubscriptionTable{}, Flagged: true},
+		{Name: "paused", TablesNotReady: []SubscriptionTable{}},
+	}, subscriptions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresSubscriptionsNone(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	mock.ExpectQuery(regexp.QuoteMeta(postgresSubscriptionsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"subname", "subenabled", "worker_running", "last_msg_send_time", "last_msg_receipt_time", "latest_end_time"}))
+	subscriptions, err := p.subscriptions(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Subscription{}, subscriptions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresReplicationSubscriptionsFailing(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_is_in_recovery()")).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(false))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresPrimaryTimelineQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"timeline"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresStandbysQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"application_name", "client_addr", "state", "sync_state", "write_lag", "flush_lag", "replay_lag", "replay_lag_bytes"}))
+	expectServerVersion(mock, 160002)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(postgresSlotsQuery, "COALESCE(wal_status, '')"))).
+		WillReturnRows(sqlmock.NewRows([]string{"slot_name", "slot_type", "database", "active", "retained_bytes", "confirmed_lag_bytes", "wal_status"}))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresSyncQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"synchronous_standby_names", "synchronous_commit"}).AddRow("", "on"))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresSubscriptionsQuery)).
+		WillReturnError(fmt.Errorf("permission denied for table pg_subscription"))
+	replication, err := p.Replication(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, RolePrimary, replication.Role)
+	assert.NotNil(t, replication.Sync)
+	assert.Nil(t, replication.Subscriptions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
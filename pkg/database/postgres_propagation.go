@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// postgresTokenPollInterval is how often a replica is checked for a token.
+const postgresTokenPollInterval = 20 * time.Millisecond
+
+// WriteToken writes token to the test table the same way TestWrite does.
+func (p *Postgres) WriteToken(ctx context.Context, token string) error {
+	log.Debug().Msgf("%s: Writing propagation token %s", p.identifier, token)
+	if p.db == nil {
+		log.Debug().Msgf("%s: No connection, connecting", p.identifier)
+		err := p.Connect()
+		if err != nil {
+			return err
+		}
+		defer p.Close()
+	}
+	return p.write(ctx, token)
+}
+
+// WaitForToken polls the test table until token is visible or ctx is done.
+func (p *Postgres) WaitForToken(ctx context.Context, token string) error {
+	log.Debug().Msgf("%s: Waiting for propagation token %s", p.identifier, token)
+	db, err := sql.Open("postgres", p.Config.postgresConnectionString())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return waitForToken(ctx, db, token)
+}
+
+func waitForToken(ctx context.Context, db *sql.DB, token string) error {
+	ticker := time.NewTicker(postgresTokenPollInterval)
+	defer ticker.Stop()
+	for {
+		var visible bool
+		err := db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM test WHERE test = $1)", token).Scan(&visible)
+		if err != nil {
+			return err
+		}
+		if visible {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeleteToken removes token from the test table.
+func (p *Postgres) DeleteToken(ctx context.Context, token string) error {
+	log.Debug().Msgf("%s: Deleting propagation token %s", p.identifier, token)
+	if p.db == nil {
+		log.Debug().Msgf("%s: No connection, connecting", p.identifier)
+		err := p.Connect()
+		if err != nil {
+			return err
+		}
+		defer p.Close()
+	}
+	_, err := p.db.ExecContext(ctx, "DELETE FROM test WHERE test = $1", token)
+	return err
+}
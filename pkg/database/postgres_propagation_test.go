@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresWriteToken(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO test (test) VALUES ($1)")).
+		WithArgs("dbm-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, p.WriteToken(context.Background(), "dbm-1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresDeleteToken(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM test WHERE test = $1")).
+		WithArgs("dbm-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, p.DeleteToken(context.Background(), "dbm-1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWaitForToken(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	query := regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM test WHERE test = $1)")
+	mock.ExpectQuery(query).WithArgs("dbm-1").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery(query).WithArgs("dbm-1").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	assert.NoError(t, waitForToken(context.Background(), p.db, "dbm-1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWaitForTokenTimeout(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	query := regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM test WHERE test = $1)")
+	for i := 0; i < 10; i++ {
+		mock.ExpectQuery(query).WithArgs("dbm-1").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*postgresTokenPollInterval)
+	defer cancel()
+	start := time.Now()
+	assert.Error(t, waitForToken(ctx, p.db, "dbm-1"))
+	assert.Less(t, time.Since(start), time.Second)
+}
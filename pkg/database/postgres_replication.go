@@ -0,0 +1,200 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// The timeline of a primary is encoded in the first eight hex digits of the
+// WAL file it is currently writing to.
+const postgresPrimaryTimelineQuery = `SELECT ('x' || substr(pg_walfile_name(pg_current_wal_lsn()), 1, 8))::bit(32)::bigint`
+
+// A standby follows the timeline it receives from its upstream. Without a
+// running WAL receiver (e.g. restoring from archive) the last checkpoint's
+// timeline is used.
+const postgresStandbyQuery = `SELECT
+	COALESCE((SELECT received_tli FROM pg_stat_wal_receiver), (SELECT timeline_id FROM pg_control_checkpoint())),
+	COALESCE((SELECT sender_host || ':' || sender_port FROM pg_stat_wal_receiver), '')`
+
+// The replay timestamp only moves when the primary commits, so a streaming
+// standby that has replayed everything it received is treated as not
+// lagging. Without a streaming receiver nothing new arrives and the age of
+// the last replayed transaction is the lag.
+const postgresStandbyLagQuery = `SELECT
+	COALESCE(CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn()
+			AND EXISTS (SELECT 1 FROM pg_stat_wal_receiver WHERE status = 'streaming') THEN 0
+		ELSE extract(epoch FROM now() - pg_last_xact_replay_timestamp()) END, 0),
+	COALESCE(pg_wal_lsn_diff(pg_last_wal_receive_lsn(), pg_last_wal_replay_lsn()), 0)::bigint`
+
+const postgresStandbysQuery = `SELECT
+	application_name,
+	COALESCE(client_addr::text, ''),
+	state,
+	sync_state,
+	COALESCE(extract(epoch FROM write_lag), 0),
+	COALESCE(extract(epoch FROM flush_lag), 0),
+	COALESCE(extract(epoch FROM replay_lag), 0),
+	COALESCE(pg_wal_lsn_diff(pg_current_wal_lsn(), replay_lsn), 0)::bigint
+FROM pg_stat_replication`
+
+// Slots retain WAL from their restart_lsn up to the current position, which
+// on a standby is the last replayed LSN.
+const postgresSlotsQuery = `SELECT
+	slot_name,
+	slot_type,
+	COALESCE(database, ''),
+	active,
+	COALESCE(pg_wal_lsn_diff(
+		CASE WHEN pg_is_in_recovery() THEN pg_last_wal_replay_lsn() ELSE pg_current_wal_lsn() END,
+		restart_lsn), 0)::bigint,
+	COALESCE(pg_wal_lsn_diff(
+		CASE WHEN pg_is_in_recovery() THEN pg_last_wal_replay_lsn() ELSE pg_current_wal_lsn() END,
+		confirmed_flush_lsn), 0)::bigint,
+	%s
+FROM pg_replication_slots
+ORDER BY slot_name`
+
+// wal_status is available from PostgreSQL 13 on.
+const postgresSlotsWALStatusVersion = 130000
+
+const defaultMaxSlotRetainedBytes = 1 << 30
+
+func (c *Config) maxSlotRetainedBytes() int64 {
+	if c.MaxSlotRetainedBytes == 0 {
+		return defaultMaxSlotRetainedBytes
+	}
+	return c.MaxSlotRetainedBytes
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func (p *Postgres) Replication(ctx context.Context) (*Replication, error) {
+	log.Debug().Msgf("%s: Detecting replication role", p.identifier)
+	if p.db == nil {
+		log.Debug().Msgf("%s: No connection, connecting", p.identifier)
+		err := p.Connect()
+		if err != nil {
+			return nil, err
+		}
+		defer p.Close()
+	}
+	replication, err := p.replication(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if p.Config.PatroniURL != "" {
+		replication.Patroni = p.patroni(ctx, replication)
+	}
+	return replication, nil
+}
+
+// replication fails only when the role, timeline or lag can't be read. Slots,
+// the sync policy and subscriptions are optional: failing to read them, e.g.
+// for lack of privileges, is logged and leaves them out.
+func (p *Postgres) replication(ctx context.Context) (*Replication, error) {
+	var inRecovery bool
+	err := p.db.QueryRowContext(ctx, "SELECT pg_is_in_recovery()").Scan(&inRecovery)
+	if err != nil {
+		return nil, err
+	}
+	replication := &Replication{}
+	if !inRecovery {
+		log.Debug().Msgf("%s: Node is primary", p.identifier)
+		replication.Role = RolePrimary
+		err = p.db.QueryRowContext(ctx, postgresPrimaryTimelineQuery).Scan(&replication.Timeline)
+		if err != nil {
+			return nil, err
+		}
+		replication.Standbys, err = p.standbys(ctx)
+		if err != nil {
+			return nil, err
+		}
+		replication.Slots, err = p.slots(ctx)
+		if err != nil {
+			log.Error().Msgf("%s: reading replication slots: %s", p.identifier, err)
+		}
+		replication.Sync, err = p.sync(ctx, replication.Standbys)
+		if err != nil {
+			log.Error().Msgf("%s: reading synchronous replication policy: %s", p.identifier, err)
+		}
+		replication.Subscriptions, err = p.subscriptions(ctx)
+		if err != nil {
+			log.Error().Msgf("%s: reading subscriptions: %s", p.identifier, err)
+		}
+		return replication, nil
+	}
+	log.Debug().Msgf("%s: Node is standby", p.identifier)
+	replication.Role = RoleStandby
+	err = p.db.QueryRowContext(ctx, postgresStandbyQuery).Scan(&replication.Timeline, &replication.Upstream)
+	if err != nil {
+		return nil, err
+	}
+	var replayLag float64
+	err = p.db.QueryRowContext(ctx, postgresStandbyLagQuery).Scan(&replayLag, &replication.ReplayLagBytes)
+	if err != nil {
+		return nil, err
+	}
+	replication.ReplayLag = secondsToDuration(replayLag)
+	replication.Slots, err = p.slots(ctx)
+	if err != nil {
+		log.Error().Msgf("%s: reading replication slots: %s", p.identifier, err)
+	}
+	return replication, nil
+}
+
+func (p *Postgres) standbys(ctx context.Context) ([]Standby, error) {
+	log.Debug().Msgf("%s: Reading standbys", p.identifier)
+	rows, err := p.db.QueryContext(ctx, postgresStandbysQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	standbys := []Standby{}
+	for rows.Next() {
+		standby := Standby{}
+		var writeLag, flushLag, replayLag float64
+		err = rows.Scan(&standby.Name, &standby.Address, &standby.State, &standby.SyncState, &writeLag, &flushLag, &replayLag, &standby.ReplayLagBytes)
+		if err != nil {
+			return nil, err
+		}
+		standby.WriteLag = secondsToDuration(writeLag)
+		standby.FlushLag = secondsToDuration(flushLag)
+		standby.ReplayLag = secondsToDuration(replayLag)
+		standbys = append(standbys, standby)
+	}
+	return standbys, rows.Err()
+}
+
+func (p *Postgres) slots(ctx context.Context) ([]Slot, error) {
+	log.Debug().Msgf("%s: Reading replication slots", p.identifier)
+	version, err := p.serverVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	walStatus := "''"
+	if version >= postgresSlotsWALStatusVersion {
+		walStatus = "COALESCE(wal_status, '')"
+	}
+	rows, err := p.db.QueryContext(ctx, fmt.Sprintf(postgresSlotsQuery, walStatus))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	slots := []Slot{}
+	maxRetainedBytes := p.Config.maxSlotRetainedBytes()
+	for rows.Next() {
+		slot := Slot{}
+		err = rows.Scan(&slot.Name, &slot.Type, &slot.Database, &slot.Active, &slot.RetainedBytes, &slot.ConfirmedLagBytes, &slot.WALStatus)
+		if err != nil {
+			return nil, err
+		}
+		slot.Flagged = !slot.Active || slot.RetainedBytes > maxRetainedBytes || slot.ConfirmedLagBytes > maxRetainedBytes || slot.WALStatus == "lost"
+		slots = append(slots, slot)
+	}
+	return slots, rows.Err()
+}
@@ -0,0 +1,207 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMockPostgres(t *testing.T) (*Postgres, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("creating sqlmock: %s", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+	p := NewPostgres(Config{Host: "localhost", Port: 5432, Database: "postgres"}).(*Postgres)
+	p.db = db
+	return p, mock
+}
+
+func expectServerVersion(mock sqlmock.Sqlmock, version int) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT current_setting('server_version_num')::int")).
+		WillReturnRows(sqlmock.NewRows([]string{"server_version_num"}).AddRow(version))
+}
+
+func TestPostgresReplicationPrimary(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_is_in_recovery()")).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(false))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresPrimaryTimelineQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"timeline"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresStandbysQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"application_name", "client_addr", "state", "sync_state", "write_lag", "flush_lag", "replay_lag", "replay_lag_bytes"}).
+			AddRow("replica1", "10.0.0.2", "streaming", "sync", 0.001, 0.002, 0.5, 1024))
+	expectServerVersion(mock, 160002)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(postgresSlotsQuery, "COALESCE(wal_status, '')"))).
+		WillReturnRows(sqlmock.NewRows([]string{"slot_name", "slot_type", "database", "active", "retained_bytes", "confirmed_lag_bytes", "wal_status"}).
+			AddRow("replica1", "physical", "", true, 1024, 0, "reserved"))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresSyncQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"synchronous_standby_names", "synchronous_commit"}).AddRow("replica1", "on"))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresSubscriptionsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"subname", "subenabled", "worker_running", "last_msg_send_time", "last_msg_receipt_time", "latest_end_time"}))
+	replication, err := p.Replication(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &Replication{
+		Role:     RolePrimary,
+		Timeline: 3,
+		Standbys: []Standby{
+			{
+				Name:           "replica1",
+				Address:        "10.0.0.2",
+				State:          "streaming",
+				SyncState:      "sync",
+				WriteLag:       time.Millisecond,
+				FlushLag:       2 * time.Millisecond,
+				ReplayLag:      500 * time.Millisecond,
+				ReplayLagBytes: 1024,
+			},
+		},
+		Slots: []Slot{
+			{Name: "replica1", Type: "physical", Active: true, RetainedBytes: 1024, WALStatus: "reserved"},
+		},
+		Sync: &Sync{
+			StandbyNames:      "replica1",
+			SynchronousCommit: "on",
+			Method:            "first",
+			Required:          1,
+			Candidates:        []string{"replica1"},
+			Attached:          1,
+		},
+		Subscriptions: []Subscription{},
+	}, replication)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresReplicationStandby(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_is_in_recovery()")).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(true))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresStandbyQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"timeline", "upstream"}).AddRow(3, "10.0.0.1:5432"))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresStandbyLagQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"replay_lag", "replay_lag_bytes"}).AddRow(1.5, 4096))
+	expectServerVersion(mock, 160002)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(postgresSlotsQuery, "COALESCE(wal_status, '')"))).
+		WillReturnRows(sqlmock.NewRows([]string{"slot_name", "slot_type", "database", "active", "retained_bytes", "confirmed_lag_bytes", "wal_status"}))
+	replication, err := p.Replication(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &Replication{
+		Role:           RoleStandby,
+		Timeline:       3,
+		Upstream:       "10.0.0.1:5432",
+		ReplayLag:      1500 * time.Millisecond,
+		ReplayLagBytes: 4096,
+		Slots:          []Slot{},
+	}, replication)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresReplicationStandbyDisconnected(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_is_in_recovery()")).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(true))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresStandbyQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"timeline", "upstream"}).AddRow(3, ""))
+	// Received and replayed positions match, but without a streaming
+	// receiver the age of the last replayed transaction counts.
+	mock.ExpectQuery(regexp.QuoteMeta(postgresStandbyLagQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"replay_lag", "replay_lag_bytes"}).AddRow(600, 0))
+	expectServerVersion(mock, 160002)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(postgresSlotsQuery, "COALESCE(wal_status, '')"))).
+		WillReturnRows(sqlmock.NewRows([]string{"slot_name", "slot_type", "database", "active", "retained_bytes", "confirmed_lag_bytes", "wal_status"}))
+	replication, err := p.Replication(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, replication.ReplayLag)
+	assert.Equal(t, "", replication.Upstream)
+	assert.Contains(t, postgresStandbyLagQuery, "status = 'streaming'")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresReplicationSlotsFailing(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_is_in_recovery()")).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(true))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresStandbyQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"timeline", "upstream"}).AddRow(3, "10.0.0.1:5432"))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresStandbyLagQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"replay_lag", "replay_lag_bytes"}).AddRow(1.5, 4096))
+	expectServerVersion(mock, 160002)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(postgresSlotsQuery, "COALESCE(wal_status, '')"))).
+		WillReturnError(fmt.Errorf("permission denied for view pg_replication_slots"))
+	replication, err := p.Replication(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &Replication{
+		Role:           RoleStandby,
+		Timeline:       3,
+		Upstream:       "10.0.0.1:5432",
+		ReplayLag:      1500 * time.Millisecond,
+		ReplayLagBytes: 4096,
+	}, replication)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresReplicationSyncFailing(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_is_in_recovery()")).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(false))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresPrimaryTimelineQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"timeline"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresStandbysQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"application_name", "client_addr", "state", "sync_state", "write_lag", "flush_lag", "replay_lag", "replay_lag_bytes"}))
+	expectServerVersion(mock, 160002)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(postgresSlotsQuery, "COALESCE(wal_status, '')"))).
+		WillReturnRows(sqlmock.NewRows([]string{"slot_name", "slot_type", "database", "active", "retained_bytes", "confirmed_lag_bytes", "wal_status"}))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresSyncQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"synchronous_standby_names", "synchronous_commit"}).AddRow("FIRST 1 (", "on"))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresSubscriptionsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"subname", "subenabled", "worker_running", "last_msg_send_time", "last_msg_receipt_time", "latest_end_time"}))
+	replication, err := p.Replication(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, RolePrimary, replication.Role)
+	assert.Equal(t, int64(3), replication.Timeline)
+	assert.Nil(t, replication.Sync)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresSlots(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	p.Config.MaxSlotRetainedBytes = 1000
+	expectServerVersion(mock, 120005)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(postgresSlotsQuery, "''"))).
+		WillReturnRows(sqlmock.NewRows([]string{"slot_name", "slot_type", "database", "active", "retained_bytes", "confirmed_lag_bytes", "wal_status"}).
+			AddRow("active", "physical", "", true, 10, 0, "").
+			AddRow("inactive", "logical", "app", false, 10, 10, "").
+			AddRow("retaining", "physical", "", true, 2000, 0, "").
+			AddRow("lagging", "logical", "app", true, 10, 2000, ""))
+	slots, err := p.slots(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Slot{
+		{Name: "active", Type: "physical", Active: true, RetainedBytes: 10},
+		{Name: "inactive", Type: "logical", Database: "app", Active: false, RetainedBytes: 10, ConfirmedLagBytes: 10, Flagged: true},
+		{Name: "retaining", Type: "physical", Active: true, RetainedBytes: 2000, Flagged: true},
+		{Name: "lagging", Type: "logical", Database: "app", Active: true, RetainedBytes: 10, ConfirmedLagBytes: 2000, Flagged: true},
+	}, slots)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresSlotsDefaultThreshold(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	expectServerVersion(mock, 160002)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(postgresSlotsQuery, "COALESCE(wal_status, '')"))).
+		WillReturnRows(sqlmock.NewRows([]string{"slot_name", "slot_type", "database", "active", "retained_bytes", "confirmed_lag_bytes", "wal_status"}).
+			AddRow("small", "physical", "", true, 1<<29, 0, "reserved").
+			AddRow("large", "physical", "", true, 1<<31, 0, "extended").
+			AddRow("lost", "physical", "", true, 0, 0, "lost"))
+	slots, err := p.slots(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, false, slots[0].Flagged)
+	assert.Equal(t, true, slots[1].Flagged)
+	assert.Equal(t, true, slots[2].Flagged)
+}
@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const defaultSequenceThreshold = 0.8
+
+// Sequences are owned by a column either through serial (deptype 'a') or as
+// an identity column (deptype 'i'). Descending sequences are left out.
+const postgresSequencesQuery = `SELECT
+	s.schemaname || '.' || s.sequencename,
+	COALESCE(s.last_value, 0),
+	s.max_value,
+	COALESCE(d.refobjid::regclass::text, ''),
+	COALESCE(a.attname, ''),
+	COALESCE(format_type(a.atttypid, a.atttypmod), '')
+FROM pg_sequences s
+LEFT JOIN pg_depend d ON d.classid = 'pg_class'::regclass
+	AND d.objid = (quote_ident(s.schemaname) || '.' || quote_ident(s.sequencename))::regclass
+	AND d.refclassid = 'pg_class'::regclass
+	AND d.deptype IN ('a', 'i')
+LEFT JOIN pg_attribute a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid
+WHERE s.increment_by > 0
+ORDER BY 1`
+
+// postgresColumnMaxValues are the largest values of the integer types a
+// sequence can feed.
+var postgresColumnMaxValues = map[string]int64{
+	"smallint": math.MaxInt16,
+	"integer":  math.MaxInt32,
+	"bigint":   math.MaxInt64,
+}
+
+func (c *Config) sequenceThreshold() float64 {
+	if c.SequenceThreshold == 0 {
+		return defaultSequenceThreshold
+	}
+	return c.SequenceThreshold
+}
+
+// Sequences returns nil unless check_sequences is enabled.
+func (p *Postgres) Sequences(ctx context.Context) (*Sequences, error) {
+	if !p.Config.CheckSequences {
+		return nil, nil
+	}
+	log.Debug().Msgf("%s: Checking sequences", p.identifier)
+	db, err := sql.Open("postgres", p.Config.postgresConnectionString())
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return p.sequences(ctx, db)
+}
+
+func (p *Postgres) sequences(ctx context.Context, db *sql.DB) (*Sequences, error) {
+	rows, err := db.QueryContext(ctx, postgresSequencesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	sequences := &Sequences{
+		Timestamp: time.Now(),
+		Threshold: p.Config.sequenceThreshold(),
+		Sequences: []Sequence{},
+	}
+	for rows.Next() {
+		sequence := Sequence{}
+		err = rows.Scan(&sequence.Name, &sequence.LastValue, &sequence.MaxValue, &sequence.Table, &sequence.Column, &sequence.ColumnType)
+		if err != nil {
+			return nil, err
+		}
+		if columnMax, ok := postgresColumnMaxValues[sequence.ColumnType]; ok && columnMax < sequence.MaxValue {
+			sequence.MaxValue = columnMax
+		}
+		if sequence.MaxValue <= 0 {
+			continue
+		}
+		sequence.Used = float64(sequence.LastValue) / float64(sequence.MaxValue)
+		if sequence.Used > sequences.Threshold {
+			sequences.Sequences = append(sequences.Sequences, sequence)
+		}
+	}
+	return sequences, rows.Err()
+}
@@ -0,0 +1,41 @@
+package database
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresSequences(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("creating sqlmock: %s", err)
+	}
+	defer db.Close()
+	p := NewPostgres(Config{Host: "localhost", Port: 5432, Database: "postgres", CheckSequences: true, SequenceThreshold: 0.5}).(*Postgres)
+	mock.ExpectQuery(regexp.QuoteMeta(postgresSequencesQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "last_value", "max_value", "table", "column", "type"}).
+			AddRow("public.orders_id_seq", 1500000000, int64(math.MaxInt64), "public.orders", "id", "integer").
+			AddRow("public.events_id_seq", 1500000000, int64(math.MaxInt64), "public.events", "id", "bigint").
+			AddRow("public.tickets", 60, 100, "", "", "").
+			AddRow("public.unused", 0, 100, "", "", ""))
+	sequences, err := p.sequences(context.Background(), db)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, sequences.Threshold)
+	assert.Equal(t, []Sequence{
+		{Name: "public.orders_id_seq", Table: "public.orders", Column: "id", ColumnType: "integer", LastValue: 1500000000, MaxValue: math.MaxInt32, Used: 1500000000.0 / math.MaxInt32},
+		{Name: "public.tickets", LastValue: 60, MaxValue: 100, Used: 0.6},
+	}, sequences.Sequences)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresSequencesDisabled(t *testing.T) {
+	p := NewPostgres(Config{Host: "localhost", Port: 5432, Database: "postgres"}).(*Postgres)
+	sequences, err := p.Sequences(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, sequences)
+}
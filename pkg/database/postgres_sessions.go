@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultLongQueryThreshold         = 300
+	defaultIdleInTransactionThreshold = 60
+	defaultLongTransactionThreshold   = 600
+)
+
+// Query texts are cut to postgresSessionQueryLength characters.
+const postgresSessionQueryLength = 1024
+
+// A transaction running many short queries only shows in xact_start, so it
+// is checked next to the query and idle times.
+const postgresSessionsQuery = `SELECT * FROM (
+	SELECT
+		pid,
+		COALESCE(usename, ''),
+		COALESCE(application_name, ''),
+		state,
+		extract(epoch FROM now() - CASE WHEN state = 'active' THEN query_start ELSE state_change END) AS duration,
+		COALESCE(extract(epoch FROM now() - xact_start), 0),
+		COALESCE(wait_event_type, ''),
+		COALESCE(wait_event, ''),
+		left(query, $3),
+		COALESCE(state = 'active' AND now() - query_start > make_interval(secs => $1), false) AS long_query,
+		COALESCE(state IN ('idle in transaction', 'idle in transaction (aborted)') AND now() - state_change > make_interval(secs => $2), false) AS idle_in_transaction,
+		COALESCE(now() - xact_start > make_interval(secs => $4), false) AS long_transaction
+	FROM pg_stat_activity
+	WHERE backend_type = 'client backend'
+		AND pid <> pg_backend_pid()
+) sessions
+WHERE long_query OR idle_in_transaction OR long_transaction
+ORDER BY duration DESC`
+
+func (c *Config) longQueryThreshold() int {
+	if c.LongQueryThreshold == 0 {
+		return defaultLongQueryThreshold
+	}
+	return c.LongQueryThreshold
+}
+
+func (c *Config) idleInTransactionThreshold() int {
+	if c.IdleInTransactionThreshold == 0 {
+		return defaultIdleInTransactionThreshold
+	}
+	return c.IdleInTransactionThreshold
+}
+
+func (c *Config) longTransactionThreshold() int {
+	if c.LongTransactionThreshold == 0 {
+		return defaultLongTransactionThreshold
+	}
+	return c.LongTransactionThreshold
+}
+
+func (p *Postgres) Sessions(ctx context.Context) ([]Session, error) {
+	log.Debug().Msgf("%s: Reading long running sessions", p.identifier)
+	if p.db == nil {
+		log.Debug().Msgf("%s: No connection, connecting", p.identifier)
+		err := p.Connect()
+		if err != nil {
+			return nil, err
+		}
+		defer p.Close()
+	}
+	rows, err := p.db.QueryContext(ctx, postgresSessionsQuery, p.Config.longQueryThreshold(), p.Config.idleInTransactionThreshold(), postgresSessionQueryLength, p.Config.longTransactionThreshold())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	sessions := []Session{}
+	for rows.Next() {
+		session := Session{}
+		var duration, transactionDuration float64
+		err = rows.Scan(&session.PID, &session.User, &session.Application, &session.State, &duration, &transactionDuration, &session.WaitEventType, &session.WaitEvent, &session.Query, &session.LongQuery, &session.IdleInTransaction, &session.LongTransaction)
+		if err != nil {
+			return nil, err
+		}
+		session.Duration = secondsToDuration(duration)
+		session.TransactionDuration = secondsToDuration(transactionDuration)
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
@@ -0,0 +1,30 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresSessions(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	p.Config.LongQueryThreshold = 30
+	mock.ExpectQuery(regexp.QuoteMeta(postgresSessionsQuery)).
+		WithArgs(30, defaultIdleInTransactionThreshold, postgresSessionQueryLength, defaultLongTransactionThreshold).
+		WillReturnRows(sqlmock.NewRows([]string{"pid", "usename", "application_name", "state", "duration", "transaction_duration", "wait_event_type", "wait_event", "query", "long_query", "idle_in_transaction", "long_transaction"}).
+			AddRow(42, "app", "api", "active", 90.5, 90.5, "Lock", "relation", "UPDATE orders SET paid = true", true, false, false).
+			AddRow(43, "app", "worker", "idle in transaction", 75, 80, "Client", "ClientRead", "SELECT 1", false, true, false).
+			AddRow(44, "app", "batch", "active", 0.2, 900, "", "", "INSERT INTO events VALUES ($1)", false, false, true))
+	sessions, err := p.Sessions(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Session{
+		{PID: 42, User: "app", Application: "api", State: "active", Duration: 90500 * time.Millisecond, TransactionDuration: 90500 * time.Millisecond, WaitEventType: "Lock", WaitEvent: "relation", Query: "UPDATE orders SET paid = true", LongQuery: true},
+		{PID: 43, User: "app", Application: "worker", State: "idle in transaction", Duration: 75 * time.Second, TransactionDuration: 80 * time.Second, WaitEventType: "Client", WaitEvent: "ClientRead", Query: "SELECT 1", IdleInTransaction: true},
+		{PID: 44, User: "app", Application: "batch", State: "active", Duration: 200 * time.Millisecond, TransactionDuration: 900 * time.Second, Query: "INSERT INTO events VALUES ($1)", LongTransaction: true},
+	}, sessions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+const postgresSettingsQuery = `SELECT
+	name,
+	COALESCE(setting, ''),
+	COALESCE(unit, ''),
+	pending_restart
+FROM pg_settings
+ORDER BY name`
+
+func (p *Postgres) Settings(ctx context.Context) (*Settings, error) {
+	log.Debug().Msgf("%s: Reading settings", p.identifier)
+	if p.db == nil {
+		log.Debug().Msgf("%s: No connection, connecting", p.identifier)
+		err := p.Connect()
+		if err != nil {
+			return nil, err
+		}
+		defer p.Close()
+	}
+	rows, err := p.db.QueryContext(ctx, postgresSettingsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	settings := &Settings{
+		Values:         make(map[string]string),
+		PendingRestart: []string{},
+	}
+	units := make(map[string]string)
+	for rows.Next() {
+		var name, value, unit string
+		var pendingRestart bool
+		err = rows.Scan(&name, &value, &unit, &pendingRestart)
+		if err != nil {
+			return nil, err
+		}
+		settings.Values[name] = value
+		units[name] = unit
+		if pendingRestart {
+			settings.PendingRestart = append(settings.PendingRestart, name)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if p.Config.DesiredSettings != "" {
+		desired, err := loadDesiredSettings(p.Config.DesiredSettings)
+		if err != nil {
+			settings.DesiredError = err.Error()
+		} else {
+			settings.Drift = settingsDrift(desired, settings.Values, units)
+		}
+	}
+	return settings, nil
+}
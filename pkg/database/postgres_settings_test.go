@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresSettings(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	p.Config.DesiredSettings = filepath.Join(t.TempDir(), "settings.yaml")
+	assert.NoError(t, os.WriteFile(p.Config.DesiredSettings, []byte("max_connections: 200\nshared_buffers: 128MB\n"), 0o600))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresSettingsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "setting", "unit", "pending_restart"}).
+			AddRow("max_connections", "100", "", true).
+			AddRow("shared_buffers", "16384", "8kB", false))
+	settings, err := p.Settings(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &Settings{
+		Values:         map[string]string{"max_connections": "100", "shared_buffers": "16384"},
+		PendingRestart: []string{"max_connections"},
+		Drift:          []SettingDrift{{Name: "max_connections", Desired: "200", Actual: "100"}},
+	}, settings)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresSettingsDesiredUnreadable(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	p.Config.DesiredSettings = filepath.Join(t.TempDir(), "missing.yaml")
+	mock.ExpectQuery(regexp.QuoteMeta(postgresSettingsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "setting", "unit", "pending_restart"}).
+			AddRow("max_connections", "100", "", true))
+	settings, err := p.Settings(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"max_connections"}, settings.PendingRestart)
+	assert.Nil(t, settings.Drift)
+	assert.Contains(t, settings.DesiredError, "missing.yaml")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// postgresLargestRelationsLimit is how many of the largest relations are
+// reported.
+const postgresLargestRelationsLimit = 10
+
+const postgresDatabaseSizeQuery = `SELECT pg_database_size(current_database())`
+
+// TOAST tables are named after the table they belong to.
+const postgresLargestRelationsQuery = `SELECT
+	CASE WHEN c.relkind = 't' THEN COALESCE((SELECT t.oid::regclass::text FROM pg_class t WHERE t.reltoastrelid = c.oid), c.relname) ELSE c.oid::regclass::text END,
+	CASE c.relkind WHEN 'r' THEN 'table' WHEN 'i' THEN 'index' ELSE 'toast' END,
+	pg_relation_size(c.oid)
+FROM pg_class c
+WHERE c.relkind IN ('r', 'i', 't')
+ORDER BY 3 DESC, 1
+LIMIT $1`
+
+func (p *Postgres) Sizes(ctx context.Context) (*Sizes, error) {
+	log.Debug().Msgf("%s: Reading sizes", p.identifier)
+	if p.db == nil {
+		log.Debug().Msgf("%s: No connection, connecting", p.identifier)
+		err := p.Connect()
+		if err != nil {
+			return nil, err
+		}
+		defer p.Close()
+	}
+	sizes := &Sizes{
+		Timestamp:   time.Now(),
+		BudgetBytes: p.Config.SizeBudgetBytes,
+		Relations:   []RelationSize{},
+	}
+	err := p.db.QueryRowContext(ctx, postgresDatabaseSizeQuery).Scan(&sizes.DatabaseBytes)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := p.db.QueryContext(ctx, postgresLargestRelationsQuery, postgresLargestRelationsLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		relation := RelationSize{}
+		err = rows.Scan(&relation.Name, &relation.Kind, &relation.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		sizes.Relations = append(sizes.Relations, relation)
+	}
+	return sizes, rows.Err()
+}
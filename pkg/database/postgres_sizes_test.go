@@ -0,0 +1,33 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresSizes(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	p.Config.SizeBudgetBytes = 1 << 40
+	mock.ExpectQuery(regexp.QuoteMeta(postgresDatabaseSizeQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_database_size"}).AddRow(1 << 30))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresLargestRelationsQuery)).
+		WithArgs(postgresLargestRelationsLimit).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "kind", "size"}).
+			AddRow("public.events", "table", 1<<29).
+			AddRow("public.events", "toast", 1<<28).
+			AddRow("public.events_pkey", "index", 1<<27))
+	sizes, err := p.Sizes(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1<<30), sizes.DatabaseBytes)
+	assert.Equal(t, int64(1<<40), sizes.BudgetBytes)
+	assert.Equal(t, []RelationSize{
+		{Name: "public.events", Kind: "table", Bytes: 1 << 29},
+		{Name: "public.events", Kind: "toast", Bytes: 1 << 28},
+		{Name: "public.events_pkey", Kind: "index", Bytes: 1 << 27},
+	}, sizes.Relations)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
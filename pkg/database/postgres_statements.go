@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+)
+
+const postgresStatementsInstalledQuery = `SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_stat_statements')`
+
+// Statements of all users are summed per query id. The time column is
+// filled in by server version.
+const postgresStatementsQuery = `SELECT
+	queryid,
+	left(min(query), $1),
+	sum(calls)::bigint,
+	sum(%s)::float8,
+	sum(rows)::bigint,
+	sum(shared_blks_read)::bigint
+FROM pg_stat_statements
+WHERE dbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+	AND queryid IS NOT NULL
+GROUP BY queryid`
+
+// pg_stat_statements 1.8, shipped with PostgreSQL 13, renamed total_time to
+// total_exec_time.
+const postgresStatementsExecTimeVersion = 130000
+
+// Statements returns nothing when pg_stat_statements isn't installed in the
+// database.
+func (p *Postgres) Statements(ctx context.Context) ([]Statement, error) {
+	log.Debug().Msgf("%s: Reading statement statistics", p.identifier)
+	if p.db == nil {
+		log.Debug().Msgf("%s: No connection, connecting", p.identifier)
+		err := p.Connect()
+		if err != nil {
+			return nil, err
+		}
+		defer p.Close()
+	}
+	var installed bool
+	err := p.db.QueryRowContext(ctx, postgresStatementsInstalledQuery).Scan(&installed)
+	if err != nil {
+		return nil, err
+	}
+	if !installed {
+		log.Debug().Msgf("%s: pg_stat_statements not installed", p.identifier)
+		return nil, nil
+	}
+	version, err := p.serverVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	timeColumn := "total_time"
+	if version >= postgresStatementsExecTimeVersion {
+		timeColumn = "total_exec_time"
+	}
+	rows, err := p.db.QueryContext(ctx, fmt.Sprintf(postgresStatementsQuery, timeColumn), postgresSessionQueryLength)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	statements := []Statement{}
+	for rows.Next() {
+		statement := Statement{}
+		var totalTime float64
+		err = rows.Scan(&statement.QueryID, &statement.Query, &statement.Calls, &totalTime, &statement.Rows, &statement.SharedBlocksRead)
+		if err != nil {
+			return nil, err
+		}
+		// pg_stat_statements measures in milliseconds.
+		statement.TotalTime = secondsToDuration(totalTime / 1000)
+		statements = append(statements, statement)
+	}
+	return statements, rows.Err()
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func expectStatementsInstalled(mock sqlmock.Sqlmock, installed bool) {
+	mock.ExpectQuery(regexp.QuoteMeta(postgresStatementsInstalledQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(installed))
+}
+
+func TestPostgresStatements(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	expectStatementsInstalled(mock, true)
+	expectServerVersion(mock, 160002)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(postgresStatementsQuery, "total_exec_time"))).
+		WithArgs(postgresSessionQueryLength).
+		WillReturnRows(sqlmock.NewRows([]string{"queryid", "query", "calls", "total_time", "rows", "shared_blks_read"}).
+			AddRow(42, "SELECT * FROM orders WHERE id = $1", 1000, 2500.0, 1000, 30))
+	statements, err := p.Statements(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Statement{
+		{QueryID: 42, Query: "SELECT * FROM orders WHERE id = $1", Calls: 1000, TotalTime: 2500 * time.Millisecond, Rows: 1000, SharedBlocksRead: 30},
+	}, statements)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresStatementsTotalTime(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	expectStatementsInstalled(mock, true)
+	expectServerVersion(mock, 120000)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(postgresStatementsQuery, "total_time"))).
+		WithArgs(postgresSessionQueryLength).
+		WillReturnRows(sqlmock.NewRows([]string{"queryid", "query", "calls", "total_time", "rows", "shared_blks_read"}))
+	statements, err := p.Statements(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Statement{}, statements)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresStatementsNotInstalled(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	expectStatementsInstalled(mock, false)
+	statements, err := p.Statements(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, statements)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
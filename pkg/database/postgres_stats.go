@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const postgresDatabaseStatsQuery = `SELECT
+	COALESCE(sum(blks_hit), 0)::bigint,
+	COALESCE(sum(blks_read), 0)::bigint,
+	COALESCE(sum(temp_files), 0)::bigint,
+	COALESCE(sum(temp_bytes), 0)::bigint
+FROM pg_stat_database`
+
+const postgresBgwriterStatsQuery = `SELECT
+	checkpoints_timed,
+	checkpoints_req,
+	buffers_checkpoint,
+	buffers_clean,
+	buffers_backend
+FROM pg_stat_bgwriter`
+
+// PostgreSQL 17 moved the checkpoint counters to pg_stat_checkpointer and
+// the writes of backends to pg_stat_io.
+const postgresCheckpointerStatsQuery = `SELECT
+	c.num_timed,
+	c.num_requested,
+	c.buffers_written,
+	b.buffers_clean,
+	(SELECT COALESCE(sum(writes), 0)::bigint FROM pg_stat_io WHERE backend_type = 'client backend')
+FROM pg_stat_checkpointer c, pg_stat_bgwriter b`
+
+const postgresCheckpointerVersion = 170000
+
+func (p *Postgres) Stats(ctx context.Context) (*Stats, error) {
+	log.Debug().Msgf("%s: Reading server statistics", p.identifier)
+	if p.db == nil {
+		log.Debug().Msgf("%s: No connection, connecting", p.identifier)
+		err := p.Connect()
+		if err != nil {
+			return nil, err
+		}
+		defer p.Close()
+	}
+	stats := &Stats{Timestamp: time.Now()}
+	err := p.db.QueryRowContext(ctx, postgresDatabaseStatsQuery).Scan(&stats.BlocksHit, &stats.BlocksRead, &stats.TempFiles, &stats.TempBytes)
+	if err != nil {
+		return nil, err
+	}
+	stats.HitRatio = hitRatio(stats.BlocksHit, stats.BlocksRead)
+	version, err := p.serverVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := postgresBgwriterStatsQuery
+	if version >= postgresCheckpointerVersion {
+		query = postgresCheckpointerStatsQuery
+	}
+	err = p.db.QueryRowContext(ctx, query).Scan(&stats.CheckpointsTimed, &stats.CheckpointsRequested, &stats.BuffersCheckpoint, &stats.BuffersClean, &stats.BuffersBackend)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
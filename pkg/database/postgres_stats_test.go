@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var postgresCheckpointColumns = []string{"timed", "requested", "buffers_checkpoint", "buffers_clean", "buffers_backend"}
+
+func expectDatabaseStats(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta(postgresDatabaseStatsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"blks_hit", "blks_read", "temp_files", "temp_bytes"}).AddRow(990, 10, 3, 3072))
+}
+
+func TestPostgresStats(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	expectDatabaseStats(mock)
+	expectServerVersion(mock, 160002)
+	mock.ExpectQuery(regexp.QuoteMeta(postgresBgwriterStatsQuery)).
+		WillReturnRows(sqlmock.NewRows(postgresCheckpointColumns).AddRow(100, 5, 2000, 300, 40))
+	stats, err := p.Stats(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &Stats{
+		Timestamp:            stats.Timestamp,
+		BlocksHit:            990,
+		BlocksRead:           10,
+		HitRatio:             0.99,
+		TempFiles:            3,
+		TempBytes:            3072,
+		CheckpointsTimed:     100,
+		CheckpointsRequested: 5,
+		BuffersCheckpoint:    2000,
+		BuffersClean:         300,
+		BuffersBackend:       40,
+	}, stats)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresStatsCheckpointer(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	expectDatabaseStats(mock)
+	expectServerVersion(mock, 170000)
+	mock.ExpectQuery(regexp.QuoteMeta(postgresCheckpointerStatsQuery)).
+		WillReturnRows(sqlmock.NewRows(postgresCheckpointColumns).AddRow(7, 1, 500, 20, 4))
+	stats, err := p.Stats(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), stats.CheckpointsTimed)
+	assert.Equal(t, int64(4), stats.BuffersBackend)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
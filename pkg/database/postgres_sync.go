@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+const postgresSyncQuery = `SELECT current_setting('synchronous_standby_names'), current_setting('synchronous_commit')`
+
+// parseSynchronousStandbyNames parses the synchronous_standby_names setting,
+// which is either "[FIRST|ANY] num_sync (name [, ...])" or the legacy
+// "name [, ...]" meaning FIRST 1.
+func parseSynchronousStandbyNames(value string) (string, int, []string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", 0, []string{}, nil
+	}
+	method := "first"
+	required := 1
+	list := value
+	if open := strings.Index(value, "("); open >= 0 {
+		if !strings.HasSuffix(value, ")") {
+			return "", 0, nil, fmt.Errorf("parsing synchronous_standby_names %q: missing closing parenthesis", value)
+		}
+		list = value[open+1 : len(value)-1]
+		prefix := strings.Fields(value[:open])
+		num := ""
+		switch len(prefix) {
+		case 1:
+			num = prefix[0]
+		case 2:
+			method = strings.ToLower(prefix[0])
+			if method != "first" && method != "any" {
+				return "", 0, nil, fmt.Errorf("parsing synchronous_standby_names %q: unknown method %s", value, prefix[0])
+			}
+			num = prefix[1]
+		default:
+			return "", 0, nil, fmt.Errorf("parsing synchronous_standby_names %q: invalid prefix", value)
+		}
+		var err error
+		required, err = strconv.Atoi(num)
+		if err != nil {
+			return "", 0, nil, fmt.Errorf("parsing synchronous_standby_names %q: %w", value, err)
+		}
+	}
+	candidates := []string{}
+	for _, name := range strings.Split(list, ",") {
+		name = strings.Trim(strings.TrimSpace(name), `"`)
+		if name != "" {
+			candidates = append(candidates, name)
+		}
+	}
+	return method, required, candidates, nil
+}
+
+// commitWaits reports whether synchronous_commit makes commits wait for
+// synchronous standbys.
+func commitWaits(synchronousCommit string) bool {
+	return synchronousCommit != "off" && synchronousCommit != "local"
+}
+
+func (p *Postgres) sync(ctx context.Context, standbys []Standby) (*Sync, error) {
+	log.Debug().Msgf("%s: Reading synchronous replication settings", p.identifier)
+	sync := &Sync{}
+	err := p.db.QueryRowContext(ctx, postgresSyncQuery).Scan(&sync.StandbyNames, &sync.SynchronousCommit)
+	if err != nil {
+		return nil, err
+	}
+	sync.Method, sync.Required, sync.Candidates, err = parseSynchronousStandbyNames(sync.StandbyNames)
+	if err != nil {
+		return nil, err
+	}
+	for _, standby := range standbys {
+		if standby.State == "streaming" && (standby.SyncState == "sync" || standby.SyncState == "quorum") {
+			sync.Attached++
+		}
+	}
+	waits := commitWaits(sync.SynchronousCommit)
+	sync.Blocking = waits && sync.Attached < sync.Required
+	expected := p.Config.ExpectedSyncStandbys
+	sync.Degraded = sync.Required < expected || (!waits && (sync.Required > 0 || expected > 0))
+	return sync, nil
+}
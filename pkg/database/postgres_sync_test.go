@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSynchronousStandbyNames(t *testing.T) {
+	tests := []struct {
+		value      string
+		method     string
+		required   int
+		candidates []string
+	}{
+		{"", "", 0, []string{}},
+		{"s1", "first", 1, []string{"s1"}},
+		{"s1, s2", "first", 1, []string{"s1", "s2"}},
+		{"2 (s1, s2, s3)", "first", 2, []string{"s1", "s2", "s3"}},
+		{"FIRST 2 (s1, s2)", "first", 2, []string{"s1", "s2"}},
+		{"ANY 2 (s1, \"s-2\", s3)", "any", 2, []string{"s1", "s-2", "s3"}},
+		{"any 1 (*)", "any", 1, []string{"*"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			method, required, candidates, err := parseSynchronousStandbyNames(tt.value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.method, method)
+			assert.Equal(t, tt.required, required)
+			assert.Equal(t, tt.candidates, candidates)
+		})
+	}
+}
+
+func TestParseSynchronousStandbyNamesInvalid(t *testing.T) {
+	for _, value := range []string{"ANY 2 (s1, s2", "SOME 2 (s1)", "ANY two (s1)", "ANY 1 2 (s1)"} {
+		_, _, _, err := parseSynchronousStandbyNames(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func expectSync(mock sqlmock.Sqlmock, standbyNames string, synchronousCommit string) {
+	mock.ExpectQuery(regexp.QuoteMeta(postgresSyncQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"synchronous_standby_names", "synchronous_commit"}).AddRow(standbyNames, synchronousCommit))
+}
+
+func TestPostgresSyncQuorum(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	expectSync(mock, "ANY 2 (s1, s2, s3)", "on")
+	sync, err := p.sync(context.Background(), []Standby{
+		{Name: "s1", State: "streaming", SyncState: "quorum"},
+		{Name: "s2", State: "streaming", SyncState: "quorum"},
+		{Name: "s3", State: "catchup", SyncState: "quorum"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, sync.Attached)
+	assert.Equal(t, false, sync.Blocking)
+	assert.Equal(t, false, sync.Degraded)
+}
+
+func TestPostgresSyncBlocking(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	expectSync(mock, "FIRST 2 (s1, s2)", "remote_apply")
+	sync, err := p.sync(context.Background(), []Standby{
+		{Name: "s1", State: "streaming", SyncState: "sync"},
+		{Name: "s2", State: "streaming", SyncState: "async"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sync.Attached)
+	assert.Equal(t, true, sync.Blocking)
+}
+
+func TestPostgresSyncCommitLocal(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	expectSync(mock, "s1", "local")
+	sync, err := p.sync(context.Background(), []Standby{})
+	assert.NoError(t, err)
+	assert.Equal(t, false, sync.Blocking)
+	assert.Equal(t, true, sync.Degraded)
+}
+
+func TestPostgresSyncDegradedToAsync(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	p.Config.ExpectedSyncStandbys = 1
+	expectSync(mock, "", "on")
+	sync, err := p.sync(context.Background(), []Standby{
+		{Name: "s1", State: "streaming", SyncState: "async"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sync.Required)
+	assert.Equal(t, false, sync.Blocking)
+	assert.Equal(t, true, sync.Degraded)
+}
+
+func TestPostgresSyncAsync(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	expectSync(mock, "", "on")
+	sync, err := p.sync(context.Background(), []Standby{})
+	assert.NoError(t, err)
+	assert.Equal(t, false, sync.Blocking)
+	assert.Equal(t, false, sync.Degraded)
+}
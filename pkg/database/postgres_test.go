@@ -1,8 +1,11 @@
 package database
 
 import (
+	"context"
+	"regexp"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -58,4 +61,20 @@ func TestPostgresConnnectionStringNoTimeout(t *testing.T) {
 	expected := "host=localhost port=5432 user=testuser password=testpassword dbname=testdb sslmode=verify-full sslcert=/path/to/cert sslkey=/path/to/key sslrootcert=/path/to/rootcert connect_timeout=5"
 	actual := cfg.postgresConnectionString()
 	assert.Equal(t, expected, actual)
+	// Other goroutines read the config concurrently, so it stays untouched.
+	assert.Equal(t, 0, cfg.ConnectionTimeout)
+}
+
+func TestNewPostgresDefaultTimeout(t *testing.T) {
+	p := NewPostgres(Config{Host: "localhost", Port: 5432, Database: "postgres"}).(*Postgres)
+	assert.Equal(t, defaultConnectionTimeout, p.Config.ConnectionTimeout)
+}
+
+func TestPostgresSetupTestTable(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	mock.ExpectExec(regexp.QuoteMeta("DROP TABLE IF EXISTS test")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE test (test varchar(255))")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE INDEX test_test_idx ON test (test)")).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.NoError(t, p.SetupTestTable(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -0,0 +1,129 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// postgresVacuumTablesLimit is how many tables with the most dead tuples are
+// reported.
+const postgresVacuumTablesLimit = 20
+
+const defaultBloatTables = 10
+
+// Tables with more than postgresAutovacuumLagFactor times the dead tuples
+// that should have triggered autovacuum are flagged.
+const postgresAutovacuumLagFactor = 2
+
+const postgresVacuumQuery = `SELECT
+	relid::regclass::text,
+	n_live_tup,
+	n_dead_tup,
+	last_vacuum,
+	last_autovacuum,
+	last_analyze,
+	last_autoanalyze,
+	current_setting('autovacuum_vacuum_threshold')::float8
+		+ current_setting('autovacuum_vacuum_scale_factor')::float8 * n_live_tup
+FROM pg_stat_user_tables
+ORDER BY n_dead_tup DESC, 1
+LIMIT $1`
+
+// Every row takes a 24 byte header and a 4 byte line pointer on top of its
+// columns' average width. Pages are only filled up to the fillfactor.
+const postgresBloatQuery = `WITH tables AS (
+	SELECT
+		c.oid,
+		n.nspname,
+		c.relname,
+		c.reltuples,
+		pg_relation_size(c.oid) AS size,
+		COALESCE((regexp_match(array_to_string(c.reloptions, ','), 'fillfactor=(\d+)'))[1]::int, 100) AS fillfactor
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE c.relkind = 'r' AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+	ORDER BY 5 DESC
+	LIMIT $1
+)
+SELECT
+	t.oid::regclass::text,
+	t.size,
+	(ceil(greatest(t.reltuples, 0) * (28 + COALESCE(sum(s.avg_width), 0))
+		/ (current_setting('block_size')::int * t.fillfactor / 100.0))
+		* current_setting('block_size')::int)::bigint
+FROM tables t
+LEFT JOIN pg_stats s ON s.schemaname = t.nspname AND s.tablename = t.relname
+GROUP BY t.oid, t.size, t.reltuples, t.fillfactor
+ORDER BY 2 DESC, 1`
+
+func (c *Config) bloatTables() int {
+	if c.BloatTables == 0 {
+		return defaultBloatTables
+	}
+	return c.BloatTables
+}
+
+// Vacuum reads the vacuum health of the tables.
+func (p *Postgres) Vacuum(ctx context.Context) (*Vacuum, error) {
+	log.Debug().Msgf("%s: Reading vacuum statistics", p.identifier)
+	db, err := sql.Open("postgres", p.Config.postgresConnectionString())
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return p.vacuum(ctx, db)
+}
+
+func (p *Postgres) vacuum(ctx context.Context, db *sql.DB) (*Vacuum, error) {
+	vacuum := &Vacuum{Timestamp: time.Now()}
+	rows, err := db.QueryContext(ctx, postgresVacuumQuery, postgresVacuumTablesLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	vacuum.Tables = []TableVacuum{}
+	for rows.Next() {
+		table := TableVacuum{}
+		var lastVacuum, lastAutovacuum, lastAnalyze, lastAutoanalyze sql.NullTime
+		var trigger float64
+		err = rows.Scan(&table.Table, &table.LiveTuples, &table.DeadTuples, &lastVacuum, &lastAutovacuum, &lastAnalyze, &lastAutoanalyze, &trigger)
+		if err != nil {
+			return nil, err
+		}
+		table.LastVacuum = nullTime(lastVacuum)
+		table.LastAutovacuum = nullTime(lastAutovacuum)
+		table.LastAnalyze = nullTime(lastAnalyze)
+		table.LastAutoanalyze = nullTime(lastAutoanalyze)
+		if total := table.LiveTuples + table.DeadTuples; total > 0 {
+			table.DeadRatio = float64(table.DeadTuples) / float64(total)
+		}
+		table.Flagged = float64(table.DeadTuples) > postgresAutovacuumLagFactor*trigger
+		vacuum.Tables = append(vacuum.Tables, table)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	bloat, err := db.QueryContext(ctx, postgresBloatQuery, p.Config.bloatTables())
+	if err != nil {
+		return nil, err
+	}
+	defer bloat.Close()
+	vacuum.Bloat = []TableBloat{}
+	for bloat.Next() {
+		table := TableBloat{}
+		var expected int64
+		err = bloat.Scan(&table.Table, &table.SizeBytes, &expected)
+		if err != nil {
+			return nil, err
+		}
+		if table.SizeBytes > expected {
+			table.BloatBytes = table.SizeBytes - expected
+			table.BloatPercent = percentOf(table.BloatBytes, table.SizeBytes)
+		}
+		vacuum.Bloat = append(vacuum.Bloat, table)
+	}
+	return vacuum, bloat.Err()
+}
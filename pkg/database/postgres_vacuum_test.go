@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresVacuum(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("creating sqlmock: %s", err)
+	}
+	defer db.Close()
+	p := NewPostgres(Config{Host: "localhost", Port: 5432, Database: "postgres", BloatTables: 2}).(*Postgres)
+	vacuumed := time.Now().Add(-time.Hour)
+	mock.ExpectQuery(regexp.QuoteMeta(postgresVacuumQuery)).
+		WithArgs(postgresVacuumTablesLimit).
+		WillReturnRows(sqlmock.NewRows([]string{"table", "n_live_tup", "n_dead_tup", "last_vacuum", "last_autovacuum", "last_analyze", "last_autoanalyze", "trigger"}).
+			AddRow("public.orders", 1000, 3000, nil, vacuumed, nil, vacuumed, 250.0).
+			AddRow("public.users", 1000, 100, nil, nil, nil, nil, 250.0).
+			AddRow("public.empty", 0, 0, nil, nil, nil, nil, 50.0))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresBloatQuery)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"table", "size", "expected"}).
+			AddRow("public.orders", 81920, 8192).
+			AddRow("public.users", 8192, 16384))
+	vacuum, err := p.vacuum(context.Background(), db)
+	assert.NoError(t, err)
+	assert.Equal(t, []TableVacuum{
+		{Table: "public.orders", LiveTuples: 1000, DeadTuples: 3000, DeadRatio: 0.75, LastAutovacuum: &vacuumed, LastAutoanalyze: &vacuumed, Flagged: true},
+		{Table: "public.users", LiveTuples: 1000, DeadTuples: 100, DeadRatio: 100.0 / 1100},
+		{Table: "public.empty"},
+	}, vacuum.Tables)
+	assert.Equal(t, []TableBloat{
+		{Table: "public.orders", SizeBytes: 81920, BloatBytes: 73728, BloatPercent: 90},
+		{Table: "public.users", SizeBytes: 8192},
+	}, vacuum.Bloat)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Both transaction and multixact IDs wrap around after 2^31.
+const wraparoundLimit = 1 << 31
+
+const defaultMaxWraparoundPercent = 50
+
+// postgresOldestTablesLimit is how many of the oldest unfrozen tables of the
+// connected database are reported.
+const postgresOldestTablesLimit = 10
+
+const postgresFreezeMaxAgeQuery = `SELECT
+	current_setting('autovacuum_freeze_max_age')::bigint,
+	current_setting('autovacuum_multixact_freeze_max_age')::bigint`
+
+const postgresDatabaseAgesQuery = `SELECT
+	datname,
+	age(datfrozenxid),
+	mxid_age(datminmxid)
+FROM pg_database
+ORDER BY 2 DESC, 1`
+
+// Only relation kinds with storage of their own carry frozen IDs.
+const postgresTableAgesQuery = `SELECT
+	c.oid::regclass::text,
+	age(c.relfrozenxid),
+	mxid_age(c.relminmxid)
+FROM pg_class c
+WHERE c.relkind IN ('r', 'm', 't')
+ORDER BY 2 DESC, 1
+LIMIT $1`
+
+func (c *Config) maxWraparoundPercent() float64 {
+	if c.MaxWraparoundPercent == 0 {
+		return defaultMaxWraparoundPercent
+	}
+	return c.MaxWraparoundPercent
+}
+
+func percentOf(value int64, limit int64) float64 {
+	if limit == 0 {
+		return 0
+	}
+	return float64(value) / float64(limit) * 100
+}
+
+func (p *Postgres) Wraparound(ctx context.Context) (*Wraparound, error) {
+	log.Debug().Msgf("%s: Reading wraparound ages", p.identifier)
+	if p.db == nil {
+		log.Debug().Msgf("%s: No connection, connecting", p.identifier)
+		err := p.Connect()
+		if err != nil {
+			return nil, err
+		}
+		defer p.Close()
+	}
+	wraparound := &Wraparound{}
+	err := p.db.QueryRowContext(ctx, postgresFreezeMaxAgeQuery).Scan(&wraparound.FreezeMaxAge, &wraparound.MultixactFreezeMaxAge)
+	if err != nil {
+		return nil, err
+	}
+	wraparound.Databases, err = p.wraparoundAges(ctx, wraparound, postgresDatabaseAgesQuery)
+	if err != nil {
+		return nil, err
+	}
+	wraparound.Tables, err = p.wraparoundAges(ctx, wraparound, postgresTableAgesQuery, postgresOldestTablesLimit)
+	if err != nil {
+		return nil, err
+	}
+	return wraparound, nil
+}
+
+func (p *Postgres) wraparoundAges(ctx context.Context, wraparound *Wraparound, query string, args ...any) ([]WraparoundAge, error) {
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ages := []WraparoundAge{}
+	maxPercent := p.Config.maxWraparoundPercent()
+	for rows.Next() {
+		age := WraparoundAge{}
+		var mxidAge sql.NullInt64
+		err = rows.Scan(&age.Name, &age.XIDAge, &mxidAge)
+		if err != nil {
+			return nil, err
+		}
+		age.MXIDAge = mxidAge.Int64
+		age.XIDFreezePercent = percentOf(age.XIDAge, wraparound.FreezeMaxAge)
+		age.XIDWraparoundPercent = percentOf(age.XIDAge, wraparoundLimit)
+		age.MXIDFreezePercent = percentOf(age.MXIDAge, wraparound.MultixactFreezeMaxAge)
+		age.MXIDWraparoundPercent = percentOf(age.MXIDAge, wraparoundLimit)
+		age.Flagged = age.XIDWraparoundPercent > maxPercent || age.MXIDWraparoundPercent > maxPercent
+		if age.Flagged {
+			wraparound.Flagged = true
+		}
+		ages = append(ages, age)
+	}
+	return ages, rows.Err()
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresWraparound(t *testing.T) {
+	p, mock := newMockPostgres(t)
+	p.Config.MaxWraparoundPercent = 40
+	mock.ExpectQuery(regexp.QuoteMeta(postgresFreezeMaxAgeQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"autovacuum_freeze_max_age", "autovacuum_multixact_freeze_max_age"}).AddRow(200000000, 400000000))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresDatabaseAgesQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"datname", "age", "mxid_age"}).
+			AddRow("app", 1073741824, 100000000).
+			AddRow("postgres", 100000000, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(postgresTableAgesQuery)).
+		WithArgs(postgresOldestTablesLimit).
+		WillReturnRows(sqlmock.NewRows([]string{"relname", "age", "mxid_age"}).
+			AddRow("public.orders", 300000000, nil))
+	wraparound, err := p.Wraparound(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &Wraparound{
+		FreezeMaxAge:          200000000,
+		MultixactFreezeMaxAge: 400000000,
+		Databases: []WraparoundAge{
+			{
+				Name:                  "app",
+				XIDAge:                1073741824,
+				XIDFreezePercent:      536.870912,
+				XIDWraparoundPercent:  50,
+				MXIDAge:               100000000,
+				MXIDFreezePercent:     25,
+				MXIDWraparoundPercent: percentOf(100000000, wraparoundLimit),
+				Flagged:               true,
+			},
+			{
+				Name:                 "postgres",
+				XIDAge:               100000000,
+				XIDFreezePercent:     50,
+				XIDWraparoundPercent: percentOf(100000000, wraparoundLimit),
+			},
+		},
+		Tables: []WraparoundAge{
+			{
+				Name:                 "public.orders",
+				XIDAge:               300000000,
+				XIDFreezePercent:     150,
+				XIDWraparoundPercent: percentOf(300000000, wraparoundLimit),
+			},
+		},
+		Flagged: true,
+	}, wraparound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
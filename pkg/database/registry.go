@@ -0,0 +1,87 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Constructor creates a backend for a single database configuration.
+type Constructor func(cfg Config) Database
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Constructor)
+)
+
+// Register makes a backend available under the given type name. Backends
+// shipped with dbm register themselves on init; code embedding dbm can call
+// Register to add its own. It panics if the name is empty, the constructor
+// is nil or the name is already taken.
+func Register(name string, constructor Constructor) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if name == "" {
+		panic("database: Register with empty name")
+	}
+	if constructor == nil {
+		panic(fmt.Sprintf("database: Register %s with nil constructor", name))
+	}
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("database: Register called twice for %s", name))
+	}
+	registry[name] = constructor
+}
+
+// Types returns the sorted names of all registered backends.
+func Types() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	types := make([]string, 0, len(registry))
+	for name := range registry {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// New creates the backend registered for cfg.Type.
+func New(cfg Config) (Database, error) {
+	registryMu.RLock()
+	constructor, ok := registry[cfg.Type]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown database type %q, valid types are: %s", cfg.Type, strings.Join(Types(), ", "))
+	}
+	return constructor(cfg), nil
+}
+
+// NewAll creates a backend for every configuration. Entries without a type
+// use defaultType.
+func NewAll(cfgs []Config, defaultType string) ([]Database, error) {
+	dbs := []Database{}
+	for i, cfg := range cfgs {
+		if cfg.Type == "" {
+			cfg.Type = defaultType
+		}
+		db, err := New(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("databases[%d]: %w", i, err)
+		}
+		dbs = append(dbs, db)
+	}
+	return dbs, nil
+}
+
+// Clusters maps the identifier of every database created by NewAll to the
+// cluster configured for it. Databases without a cluster are left out.
+func Clusters(cfgs []Config, dbs []Database) map[string]string {
+	clusters := make(map[string]string)
+	for i, db := range dbs {
+		if cfgs[i].Cluster != "" {
+			clusters[db.Identifier()] = cfgs[i].Cluster
+		}
+	}
+	return clusters
+}
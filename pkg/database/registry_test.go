@@ -0,0 +1,69 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypes(t *testing.T) {
+	assert.Subset(t, Types(), []string{"mysql", "postgres", "sqlite"})
+}
+
+func TestNew(t *testing.T) {
+	db, err := New(Config{Type: "sqlite", FilePath: "test.db"})
+	assert.NoError(t, err)
+	assert.IsType(t, &SQLite{}, db)
+	assert.Equal(t, "test.db", db.Identifier())
+}
+
+func TestNewUnknownType(t *testing.T) {
+	_, err := New(Config{Type: "oracle"})
+	assert.ErrorContains(t, err, `unknown database type "oracle"`)
+	assert.ErrorContains(t, err, "mysql, postgres, sqlite")
+}
+
+func TestRegister(t *testing.T) {
+	Register("custom", NewSQLite)
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "custom")
+		registryMu.Unlock()
+	}()
+	db, err := New(Config{Type: "custom", FilePath: "custom.db"})
+	assert.NoError(t, err)
+	assert.Equal(t, "custom.db", db.Identifier())
+	assert.Panics(t, func() { Register("custom", NewSQLite) })
+	assert.Panics(t, func() { Register("", NewSQLite) })
+	assert.Panics(t, func() { Register("nil", nil) })
+}
+
+func TestNewAll(t *testing.T) {
+	dbs, err := NewAll([]Config{
+		{FilePath: "test.db"},
+		{Type: "postgres", Host: "localhost", Port: 5432, Database: "postgres"},
+	}, "sqlite")
+	assert.NoError(t, err)
+	assert.Len(t, dbs, 2)
+	assert.IsType(t, &SQLite{}, dbs[0])
+	assert.IsType(t, &Postgres{}, dbs[1])
+}
+
+func TestNewAllUnknownType(t *testing.T) {
+	_, err := NewAll([]Config{
+		{FilePath: "test.db"},
+		{Type: "oracle"},
+	}, "sqlite")
+	assert.ErrorContains(t, err, `databases[1]: unknown database type "oracle"`)
+}
+
+func TestClusters(t *testing.T) {
+	cfgs := []Config{
+		{Type: "sqlite", FilePath: "a.db", Cluster: "main"},
+		{Type: "sqlite", FilePath: "b.db", Cluster: "main"},
+		{Type: "sqlite", FilePath: "c.db"},
+	}
+	dbs, err := NewAll(cfgs, "")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a.db": "main", "b.db": "main"}, Clusters(cfgs, dbs))
+}
@@ -0,0 +1,89 @@
+package database
+
+import "time"
+
+// Role is the replication role a node reports for itself.
+type Role string
+
+const (
+	RolePrimary Role = "primary"
+	RoleStandby Role = "standby"
+)
+
+type Replication struct {
+	Role           Role           `json:"role,omitempty"`
+	Timeline       int64          `json:"timeline,omitempty"`
+	Upstream       string         `json:"upstream,omitempty"`
+	ReplayLag      time.Duration  `json:"replay_lag,omitempty"`
+	ReplayLagBytes int64          `json:"replay_lag_bytes,omitempty"`
+	Standbys       []Standby      `json:"standbys,omitempty"`
+	Slots          []Slot         `json:"slots,omitempty"`
+	Sync           *Sync          `json:"sync,omitempty"`
+	Patroni        *Patroni       `json:"patroni,omitempty"`
+	Subscriptions  []Subscription `json:"subscriptions,omitempty"`
+}
+
+// Standby is a replica streaming from a primary as seen by the primary.
+type Standby struct {
+	Name           string        `json:"name"`
+	Address        string        `json:"address"`
+	State          string        `json:"state"`
+	SyncState      string        `json:"sync_state"`
+	WriteLag       time.Duration `json:"write_lag"`
+	FlushLag       time.Duration `json:"flush_lag"`
+	ReplayLag      time.Duration `json:"replay_lag"`
+	ReplayLagBytes int64         `json:"replay_lag_bytes"`
+}
+
+// Slot is a replication slot. Flagged slots are inactive, retain more WAL
+// than the configured threshold or, for logical slots, have a subscriber
+// lagging by more than that.
+type Slot struct {
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	Database      string `json:"database,omitempty"`
+	Active        bool   `json:"active"`
+	RetainedBytes int64  `json:"retained_bytes"`
+	// ConfirmedLagBytes is how far the subscriber of a logical slot is
+	// behind in confirming changes.
+	ConfirmedLagBytes int64  `json:"confirmed_lag_bytes,omitempty"`
+	WALStatus         string `json:"wal_status,omitempty"`
+	Flagged           bool   `json:"flagged"`
+}
+
+// Subscription is a logical replication subscription on a subscriber.
+// Flagged subscriptions are enabled but have no apply worker running.
+type Subscription struct {
+	Name                string              `json:"name"`
+	Enabled             bool                `json:"enabled"`
+	WorkerRunning       bool                `json:"worker_running"`
+	LastMessageSent     *time.Time          `json:"last_msg_send_time,omitempty"`
+	LastMessageReceived *time.Time          `json:"last_msg_receipt_time,omitempty"`
+	LatestEndTime       *time.Time          `json:"latest_end_time,omitempty"`
+	TablesNotReady      []SubscriptionTable `json:"tables_not_ready"`
+	Flagged             bool                `json:"flagged"`
+}
+
+// SubscriptionTable is a table of a subscription that hasn't reached the
+// ready state yet.
+type SubscriptionTable struct {
+	Table string `json:"table"`
+	State string `json:"state"`
+}
+
+// Sync compares the synchronous replication policy of a primary with the
+// standbys actually attached to it.
+type Sync struct {
+	StandbyNames      string   `json:"standby_names"`
+	SynchronousCommit string   `json:"synchronous_commit"`
+	Method            string   `json:"method"`
+	Required          int      `json:"required"`
+	Candidates        []string `json:"candidates"`
+	Attached          int      `json:"attached"`
+	// Blocking is set when fewer synchronous standbys are attached than
+	// required, so commits wait until one reconnects.
+	Blocking bool `json:"blocking"`
+	// Degraded is set when commits don't wait for the number of
+	// synchronous standbys the database is expected to have.
+	Degraded bool `json:"degraded"`
+}
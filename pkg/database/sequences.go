@@ -0,0 +1,23 @@
+package database
+
+import "time"
+
+// Sequences lists the sequences of a database that used up more than
+// Threshold of their range at Timestamp.
+type Sequences struct {
+	Timestamp time.Time  `json:"timestamp"`
+	Threshold float64    `json:"threshold"`
+	Sequences []Sequence `json:"sequences"`
+}
+
+// Sequence is a sequence close to exhaustion. MaxValue is the lower of the
+// sequence's own maximum and the maximum of the column owning it.
+type Sequence struct {
+	Name       string  `json:"name"`
+	Table      string  `json:"table,omitempty"`
+	Column     string  `json:"column,omitempty"`
+	ColumnType string  `json:"column_type,omitempty"`
+	LastValue  int64   `json:"last_value"`
+	MaxValue   int64   `json:"max_value"`
+	Used       float64 `json:"used"`
+}
@@ -0,0 +1,22 @@
+package database
+
+import "time"
+
+// Session is a session whose query has been running, whose transaction has
+// been idle or whose transaction has been open for longer than the configured
+// threshold; the flags tell which. Duration is measured from the start of the
+// query for active sessions and from the last state change for idle ones.
+type Session struct {
+	PID                 int           `json:"pid"`
+	User                string        `json:"user"`
+	Application         string        `json:"application"`
+	State               string        `json:"state"`
+	Duration            time.Duration `json:"duration"`
+	TransactionDuration time.Duration `json:"transaction_duration"`
+	WaitEventType       string        `json:"wait_event_type,omitempty"`
+	WaitEvent           string        `json:"wait_event,omitempty"`
+	Query               string        `json:"query"`
+	LongQuery           bool          `json:"long_query"`
+	IdleInTransaction   bool          `json:"idle_in_transaction"`
+	LongTransaction     bool          `json:"long_transaction"`
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Settings is the server configuration. Values holds every parameter by
+// name in the server's base unit; Drift lists the parameters that differ
+// from the desired state, DesiredError why that couldn't be read.
+type Settings struct {
+	Values         map[string]string `json:"-"`
+	PendingRestart []string          `json:"pending_restart,omitempty"`
+	Drift          []SettingDrift    `json:"drift,omitempty"`
+	DesiredError   string            `json:"desired_error,omitempty"`
+}
+
+// SettingDrift is a parameter whose value differs from the desired state.
+// Actual is given in Unit, the parameter's base unit.
+type SettingDrift struct {
+	Name    string `json:"name"`
+	Desired string `json:"desired"`
+	Actual  string `json:"actual"`
+	Unit    string `json:"unit,omitempty"`
+}
+
+// settingUnits converts the units parameters can be given in to bytes and
+// milliseconds respectively.
+var settingUnits = map[string]float64{
+	"B":   1,
+	"kB":  1 << 10,
+	"MB":  1 << 20,
+	"GB":  1 << 30,
+	"TB":  1 << 40,
+	"us":  0.001,
+	"ms":  1,
+	"s":   1000,
+	"min": 60 * 1000,
+	"h":   60 * 60 * 1000,
+	"d":   24 * 60 * 60 * 1000,
+}
+
+var settingValuePattern = regexp.MustCompile(`^\s*(-?[0-9.]+)\s*([a-zA-Z]*)\s*$`)
+
+// normalizeSetting brings a desired value into the form the server reports
+// it in: booleans as on/off and numbers in the parameter's base unit, e.g.
+// "128MB" becomes "16384" for a parameter measured in 8kB.
+func normalizeSetting(value string, unit string) string {
+	switch strings.ToLower(value) {
+	case "true", "yes", "on":
+		return "on"
+	case "false", "no", "off":
+		return "off"
+	}
+	match := settingValuePattern.FindStringSubmatch(value)
+	if match == nil || unit == "" {
+		return value
+	}
+	number, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return value
+	}
+	baseNumber, baseUnit := 1.0, unit
+	if base := settingValuePattern.FindStringSubmatch(unit); base != nil && base[1] != "" {
+		baseNumber, _ = strconv.ParseFloat(base[1], 64)
+		baseUnit = base[2]
+	}
+	from, ok := settingUnits[match[2]]
+	if match[2] == "" {
+		from, ok = settingUnits[baseUnit]
+		number *= baseNumber
+	}
+	to, known := settingUnits[baseUnit]
+	if !ok || !known {
+		return value
+	}
+	return strconv.FormatFloat(number*from/(to*baseNumber), 'f', -1, 64)
+}
+
+// loadDesiredSettings reads a YAML file mapping parameter names to their
+// desired values.
+func loadDesiredSettings(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	desired := make(map[string]string)
+	if err := yaml.Unmarshal(content, &desired); err != nil {
+		return nil, fmt.Errorf("parsing desired settings %s: %w", path, err)
+	}
+	return desired, nil
+}
+
+// settingsDrift compares the desired state with the actual values and units
+// of the parameters.
+func settingsDrift(desired map[string]string, values map[string]string, units map[string]string) []SettingDrift {
+	drift := []SettingDrift{}
+	for name, want := range desired {
+		name = strings.ToLower(name)
+		actual, ok := values[name]
+		if ok && strings.EqualFold(normalizeSetting(want, units[name]), actual) {
+			continue
+		}
+		drift = append(drift, SettingDrift{Name: name, Desired: want, Actual: actual, Unit: units[name]})
+	}
+	sort.Slice(drift, func(i, j int) bool {
+		return drift[i].Name < drift[j].Name
+	})
+	return drift
+}
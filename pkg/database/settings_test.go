@@ -0,0 +1,61 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeSetting(t *testing.T) {
+	tests := []struct {
+		value string
+		unit  string
+		want  string
+	}{
+		{"128MB", "8kB", "16384"},
+		{"1GB", "kB", "1048576"},
+		{"16384", "8kB", "16384"},
+		{"5min", "s", "300"},
+		{"250ms", "ms", "250"},
+		{"1h", "min", "60"},
+		{"true", "", "on"},
+		{"Off", "", "off"},
+		{"replica", "", "replica"},
+		{"100", "", "100"},
+		{"10parsecs", "s", "10parsecs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value+" "+tt.unit, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizeSetting(tt.value, tt.unit))
+		})
+	}
+}
+
+func TestSettingsDrift(t *testing.T) {
+	values := map[string]string{"max_connections": "100", "shared_buffers": "16384", "hot_standby_feedback": "off"}
+	units := map[string]string{"shared_buffers": "8kB"}
+	assert.Equal(t, []SettingDrift{
+		{Name: "hot_standby_feedback", Desired: "on", Actual: "off"},
+		{Name: "max_connections", Desired: "200", Actual: "100"},
+		{Name: "no_such_setting", Desired: "1"},
+	}, settingsDrift(map[string]string{
+		"max_connections":      "200",
+		"shared_buffers":       "128MB",
+		"HOT_STANDBY_FEEDBACK": "on",
+		"no_such_setting":      "1",
+	}, values, units))
+	assert.Equal(t, []SettingDrift{}, settingsDrift(map[string]string{"shared_buffers": "128MB"}, values, units))
+}
+
+func TestLoadDesiredSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("max_connections: 200\nhot_standby_feedback: true\nwal_level: replica\n"), 0o600))
+	desired, err := loadDesiredSettings(path)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"max_connections": "200", "hot_standby_feedback": "true", "wal_level": "replica"}, desired)
+	assert.NoError(t, os.WriteFile(path, []byte("- not a map"), 0o600))
+	_, err = loadDesiredSettings(path)
+	assert.ErrorContains(t, err, "parsing desired settings")
+}
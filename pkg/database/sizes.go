@@ -0,0 +1,19 @@
+package database
+
+import "time"
+
+// Sizes is the size of a database and of its largest relations at
+// Timestamp. BudgetBytes is the configured size budget of the database.
+type Sizes struct {
+	Timestamp     time.Time      `json:"timestamp"`
+	DatabaseBytes int64          `json:"database_bytes"`
+	BudgetBytes   int64          `json:"budget_bytes,omitempty"`
+	Relations     []RelationSize `json:"relations"`
+}
+
+// RelationSize is the size of a table, index or TOAST table.
+type RelationSize struct {
+	Name  string `json:"name"`
+	Kind  string `json:"kind"`
+	Bytes int64  `json:"bytes"`
+}
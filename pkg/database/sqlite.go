@@ -8,6 +8,10 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 )
 
+func init() {
+	Register("sqlite", NewSQLite)
+}
+
 type SQLite struct {
 	Config     Config
 	identifier string
@@ -0,0 +1,13 @@
+package database
+
+import "time"
+
+// Statement is the cumulative execution statistics of a normalized query.
+type Statement struct {
+	QueryID          int64         `json:"query_id"`
+	Query            string        `json:"query"`
+	Calls            int64         `json:"calls"`
+	TotalTime        time.Duration `json:"total_time"`
+	Rows             int64         `json:"rows"`
+	SharedBlocksRead int64         `json:"shared_blocks_read"`
+}
@@ -0,0 +1,26 @@
+package database
+
+import "time"
+
+// Stats are cumulative server statistics read at Timestamp. Block and temp
+// counters are summed over all databases of the server.
+type Stats struct {
+	Timestamp            time.Time `json:"timestamp"`
+	BlocksHit            int64     `json:"blocks_hit"`
+	BlocksRead           int64     `json:"blocks_read"`
+	HitRatio             float64   `json:"hit_ratio"`
+	TempFiles            int64     `json:"temp_files"`
+	TempBytes            int64     `json:"temp_bytes"`
+	CheckpointsTimed     int64     `json:"checkpoints_timed"`
+	CheckpointsRequested int64     `json:"checkpoints_requested"`
+	BuffersCheckpoint    int64     `json:"buffers_checkpoint"`
+	BuffersClean         int64     `json:"buffers_clean"`
+	BuffersBackend       int64     `json:"buffers_backend"`
+}
+
+func hitRatio(hit int64, read int64) float64 {
+	if hit+read == 0 {
+		return 0
+	}
+	return float64(hit) / float64(hit+read)
+}
@@ -0,0 +1,34 @@
+package database
+
+import "time"
+
+// Vacuum is the vacuum health of the tables of a database at Timestamp.
+type Vacuum struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Tables    []TableVacuum `json:"tables"`
+	Bloat     []TableBloat  `json:"bloat"`
+}
+
+// TableVacuum is the vacuum state of a table. Flagged tables have
+// accumulated far more dead tuples than should trigger autovacuum, meaning
+// autovacuum doesn't keep up.
+type TableVacuum struct {
+	Table           string     `json:"table"`
+	LiveTuples      int64      `json:"live_tuples"`
+	DeadTuples      int64      `json:"dead_tuples"`
+	DeadRatio       float64    `json:"dead_ratio"`
+	LastVacuum      *time.Time `json:"last_vacuum,omitempty"`
+	LastAutovacuum  *time.Time `json:"last_autovacuum,omitempty"`
+	LastAnalyze     *time.Time `json:"last_analyze,omitempty"`
+	LastAutoanalyze *time.Time `json:"last_autoanalyze,omitempty"`
+	Flagged         bool       `json:"flagged"`
+}
+
+// TableBloat estimates the space a table wastes from its size and the size
+// its rows would need when tightly packed.
+type TableBloat struct {
+	Table        string  `json:"table"`
+	SizeBytes    int64   `json:"size_bytes"`
+	BloatBytes   int64   `json:"bloat_bytes"`
+	BloatPercent float64 `json:"bloat_percent"`
+}
@@ -0,0 +1,26 @@
+package database
+
+// Wraparound is how close the databases of a server are to transaction ID
+// and multixact ID wraparound. Ages are given as percentages of the
+// autovacuum freeze max age, past which autovacuum forces a freeze, and of
+// the 2^31 IDs after which the server stops accepting writes.
+type Wraparound struct {
+	FreezeMaxAge          int64           `json:"autovacuum_freeze_max_age"`
+	MultixactFreezeMaxAge int64           `json:"autovacuum_multixact_freeze_max_age"`
+	Databases             []WraparoundAge `json:"databases"`
+	Tables                []WraparoundAge `json:"tables"`
+	Flagged               bool            `json:"flagged"`
+}
+
+// WraparoundAge is the age of the oldest unfrozen IDs of a database or
+// table. Flagged ages exceed the configured share of the wraparound limit.
+type WraparoundAge struct {
+	Name                  string  `json:"name"`
+	XIDAge                int64   `json:"xid_age"`
+	XIDFreezePercent      float64 `json:"xid_freeze_percent"`
+	XIDWraparoundPercent  float64 `json:"xid_wraparound_percent"`
+	MXIDAge               int64   `json:"mxid_age"`
+	MXIDFreezePercent     float64 `json:"mxid_freeze_percent"`
+	MXIDWraparoundPercent float64 `json:"mxid_wraparound_percent"`
+	Flagged               bool    `json:"flagged"`
+}